@@ -0,0 +1,530 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// 集群级资源在 /api/v1/resources/{namespace}/{kind}/{name} 中使用的命名空间占位符
+const clusterScopePlaceholder = "-"
+
+// 资源列表查询结果
+type APIResourceList struct {
+	Total  int                      `json:"total"`
+	Offset int                      `json:"offset"`
+	Limit  int                      `json:"limit"`
+	Items  []map[string]interface{} `json:"items"`
+}
+
+// 资源统计
+type APIStats struct {
+	TotalResources int            `json:"totalResources"`
+	NamespaceCount int            `json:"namespaceCount"`
+	Kinds          map[string]int `json:"kinds"`
+	Namespaces     map[string]int `json:"namespaces"`
+	Statuses       map[string]int `json:"statuses"`
+	Command        string         `json:"command"`
+	Timestamp      string         `json:"timestamp"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+// 错误信息按请求语言翻译，参数中的可翻译错误同样翻译
+func writeAPIError(w http.ResponseWriter, r *http.Request, status int, format string, args ...interface{}) {
+	writeJSON(w, status, map[string]string{"error": tr(requestLanguage(r), format, args...)})
+}
+
+// 资源摘要，object 字段包含完整对象
+func resourceSummary(index int, info ResourceInfo) map[string]interface{} {
+	return map[string]interface{}{
+		"index":      index,
+		"name":       info.Name,
+		"namespace":  info.Namespace,
+		"kind":       info.Kind,
+		"apiVersion": info.APIVersion,
+		"age":        info.Age,
+		"status":     info.Status,
+		"labels":     resourceLabels(info),
+		"object":     info.Parsed,
+	}
+}
+
+// 默认返回的摘要字段（不含 object）
+var defaultSummaryFields = []string{"index", "name", "namespace", "kind", "apiVersion", "age", "status", "labels"}
+
+// 按 fields 参数裁剪摘要字段
+func selectFields(summary map[string]interface{}, fields []string) map[string]interface{} {
+	out := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if v, ok := summary[field]; ok {
+			out[field] = v
+		}
+	}
+	return out
+}
+
+// 按查询参数过滤资源，返回匹配的索引
+func filterResources(infos []ResourceInfo, r *http.Request) ([]int, error) {
+	query := r.URL.Query()
+	kind := query.Get("kind")
+	namespace := query.Get("namespace")
+	status := query.Get("status")
+
+	// label 为 labelSelector 的简写
+	labelExpr := query.Get("labelSelector")
+	if labelExpr == "" {
+		labelExpr = query.Get("label")
+	}
+	labelSelector, err := ParseLabelSelector(labelExpr)
+	if err != nil {
+		return nil, err
+	}
+	fieldSelector, err := ParseFieldSelector(query.Get("fieldSelector"))
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []int
+	for i, info := range infos {
+		if kind != "" && !strings.EqualFold(info.Kind, kind) {
+			continue
+		}
+		if namespace != "" && info.Namespace != namespace {
+			continue
+		}
+		if status != "" && info.Status != status {
+			continue
+		}
+		if !labelSelector.Matches(resourceLabels(info)) || !fieldSelector.Matches(info) {
+			continue
+		}
+		matched = append(matched, i)
+	}
+	return matched, nil
+}
+
+// 解析非负整数参数
+func intParam(r *http.Request, name string, def int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, errorf("参数 %s 必须是非负整数: %s", name, v)
+	}
+	return n, nil
+}
+
+// GET /api/v1/resources?kind=&namespace=&label=&status=&offset=&limit=&fields=
+func apiListResourcesHandler(pageFor func(*http.Request) PageData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		infos := pageFor(r).Resources
+		matched, err := filterResources(infos, r)
+		if err != nil {
+			writeAPIError(w, r, http.StatusBadRequest, "%v", err)
+			return
+		}
+
+		offset, err := intParam(r, "offset", 0)
+		if err != nil {
+			writeAPIError(w, r, http.StatusBadRequest, "%v", err)
+			return
+		}
+		limit, err := intParam(r, "limit", 0)
+		if err != nil {
+			writeAPIError(w, r, http.StatusBadRequest, "%v", err)
+			return
+		}
+
+		fields := defaultSummaryFields
+		if v := r.URL.Query().Get("fields"); v != "" {
+			fields = strings.Split(v, ",")
+		}
+
+		result := APIResourceList{Total: len(matched), Offset: offset, Limit: limit, Items: []map[string]interface{}{}}
+		page := matched
+		if offset < len(page) {
+			page = page[offset:]
+		} else {
+			page = nil
+		}
+		if limit > 0 && limit < len(page) {
+			page = page[:limit]
+		}
+		for _, index := range page {
+			result.Items = append(result.Items, selectFields(resourceSummary(index, infos[index]), fields))
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+// GET /api/v1/resources/{namespace}/{kind}/{name}，集群级资源的命名空间写作 "-"
+func apiGetResourceHandler(pageFor func(*http.Request) PageData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/resources/"), "/"), "/")
+		if len(parts) != 3 {
+			writeAPIError(w, r, http.StatusNotFound, "路径格式应为 /api/v1/resources/{namespace}/{kind}/{name}")
+			return
+		}
+
+		namespace, kind, name := parts[0], parts[1], parts[2]
+		if namespace == clusterScopePlaceholder {
+			namespace = ""
+		}
+		for _, info := range pageFor(r).Resources {
+			if info.Namespace == namespace && strings.EqualFold(info.Kind, kind) && info.Name == name {
+				writeJSON(w, http.StatusOK, info.Parsed)
+				return
+			}
+		}
+		writeAPIError(w, r, http.StatusNotFound, "未找到资源 %s/%s/%s", parts[0], kind, name)
+	}
+}
+
+// 生成统计信息
+func buildStats(data PageData) APIStats {
+	stats := APIStats{
+		TotalResources: data.TotalResources,
+		NamespaceCount: data.NamespaceCount,
+		Kinds:          make(map[string]int),
+		Namespaces:     make(map[string]int),
+		Statuses:       make(map[string]int),
+		Command:        data.Command,
+		Timestamp:      data.Timestamp,
+	}
+	for _, info := range data.Resources {
+		stats.Kinds[info.Kind]++
+		stats.Statuses[info.Status]++
+		if info.Namespace != "" {
+			stats.Namespaces[info.Namespace]++
+		}
+	}
+	return stats
+}
+
+// GET /api/v1/stats
+func apiStatsHandler(pageFor func(*http.Request) PageData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, buildStats(pageFor(r)))
+	}
+}
+
+// GET /api/v1/openapi.json
+func apiOpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(openAPISpec))
+}
+
+// 注册 v1 API 路由
+func registerAPIv1(pageFor func(*http.Request) PageData) {
+	http.HandleFunc("/api/v1/resources", apiListResourcesHandler(pageFor))
+	http.HandleFunc("/api/v1/resources/", apiGetResourceHandler(pageFor))
+	http.HandleFunc("/api/v1/stats", apiStatsHandler(pageFor))
+	http.HandleFunc("/api/v1/query", apiQueryHandler(pageFor))
+	http.HandleFunc("/api/v1/images", apiImagesHandler(pageFor))
+	http.HandleFunc("/api/v1/lint", apiLintHandler(pageFor))
+	http.HandleFunc("/api/v1/schema", apiSchemaHandler)
+	http.HandleFunc("/api/v1/openapi.json", apiOpenAPIHandler)
+}
+
+// v1 API 的 OpenAPI 3.0 文档，描述统一使用英文，不随界面语言变化
+const openAPISpec = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "kubectl-html API",
+    "version": "v1",
+    "description": "Query the Kubernetes resources loaded by kubectl-html. Endpoints that return objects accept clean=0 to include server-side metadata such as managedFields."
+  },
+  "paths": {
+    "/api/v1/resources": {
+      "get": {
+        "summary": "List resources with filtering, pagination and field selection",
+        "parameters": [
+          { "name": "kind", "in": "query", "schema": { "type": "string" }, "description": "Resource kind, case-insensitive, e.g. Pod" },
+          { "name": "namespace", "in": "query", "schema": { "type": "string" }, "description": "Namespace" },
+          { "$ref": "#/components/parameters/labelSelector" },
+          { "$ref": "#/components/parameters/label" },
+          { "$ref": "#/components/parameters/fieldSelector" },
+          { "name": "status", "in": "query", "schema": { "type": "string", "enum": ["running", "pending", "failed", "unknown"] } },
+          { "name": "offset", "in": "query", "schema": { "type": "integer", "minimum": 0, "default": 0 } },
+          { "name": "limit", "in": "query", "schema": { "type": "integer", "minimum": 0, "default": 0 }, "description": "0 means no limit" },
+          { "name": "fields", "in": "query", "schema": { "type": "string" }, "description": "Comma-separated fields to return: index,name,namespace,kind,apiVersion,age,status,labels,object" },
+          { "$ref": "#/components/parameters/clean" },
+          { "$ref": "#/components/parameters/locale" }
+        ],
+        "responses": {
+          "200": { "description": "Resource list", "content": { "application/json": { "schema": { "$ref": "#/components/schemas/ResourceList" } } } },
+          "400": { "$ref": "#/components/responses/Error" }
+        }
+      }
+    },
+    "/api/v1/resources/{namespace}/{kind}/{name}": {
+      "get": {
+        "summary": "Get a single resource object",
+        "parameters": [
+          { "name": "namespace", "in": "path", "required": true, "schema": { "type": "string" }, "description": "Namespace, or - for cluster-scoped resources" },
+          { "name": "kind", "in": "path", "required": true, "schema": { "type": "string" } },
+          { "name": "name", "in": "path", "required": true, "schema": { "type": "string" } },
+          { "$ref": "#/components/parameters/clean" },
+          { "$ref": "#/components/parameters/locale" }
+        ],
+        "responses": {
+          "200": { "description": "Kubernetes object", "content": { "application/json": { "schema": { "type": "object" } } } },
+          "404": { "$ref": "#/components/responses/Error" }
+        }
+      }
+    },
+    "/api/v1/stats": {
+      "get": {
+        "summary": "Resource statistics",
+        "responses": {
+          "200": { "description": "Statistics", "content": { "application/json": { "schema": { "$ref": "#/components/schemas/Stats" } } } }
+        }
+      }
+    },
+    "/api/v1/query": {
+      "get": {
+        "summary": "Run a JSONPath or jq query against the loaded resources",
+        "parameters": [
+          { "name": "q", "in": "query", "required": true, "schema": { "type": "string" }, "description": "Query expression, e.g. {.spec.containers[*].image}, {range .spec.containers[*]}{.name}{\"\\n\"}{end} or .spec.containers[] | .name" },
+          { "name": "lang", "in": "query", "schema": { "type": "string", "enum": ["jsonpath", "jq"], "default": "jsonpath" } },
+          { "name": "kind", "in": "query", "schema": { "type": "string" } },
+          { "name": "namespace", "in": "query", "schema": { "type": "string" } },
+          { "$ref": "#/components/parameters/labelSelector" },
+          { "$ref": "#/components/parameters/label" },
+          { "$ref": "#/components/parameters/fieldSelector" },
+          { "name": "status", "in": "query", "schema": { "type": "string" } },
+          { "$ref": "#/components/parameters/clean" },
+          { "$ref": "#/components/parameters/locale" }
+        ],
+        "responses": {
+          "200": { "description": "Query result", "content": { "application/json": { "schema": { "$ref": "#/components/schemas/QueryResult" } } } },
+          "400": { "$ref": "#/components/responses/Error" }
+        }
+      }
+    },
+    "/api/v1/images": {
+      "get": {
+        "summary": "Container image inventory of workloads",
+        "parameters": [
+          { "name": "format", "in": "query", "schema": { "type": "string", "enum": ["json", "csv"], "default": "json" }, "description": "With csv, one row is written per image usage" },
+          { "name": "kind", "in": "query", "schema": { "type": "string" } },
+          { "name": "namespace", "in": "query", "schema": { "type": "string" } },
+          { "$ref": "#/components/parameters/labelSelector" },
+          { "$ref": "#/components/parameters/label" },
+          { "$ref": "#/components/parameters/fieldSelector" },
+          { "name": "status", "in": "query", "schema": { "type": "string" } },
+          { "$ref": "#/components/parameters/locale" }
+        ],
+        "responses": {
+          "200": {
+            "description": "Image inventory",
+            "content": {
+              "application/json": { "schema": { "$ref": "#/components/schemas/ImageInventory" } },
+              "text/csv": { "schema": { "type": "string" } }
+            }
+          },
+          "400": { "$ref": "#/components/responses/Error" }
+        }
+      }
+    },
+    "/api/v1/lint": {
+      "get": {
+        "summary": "Findings of the built-in best-practice checks",
+        "parameters": [
+          { "name": "severity", "in": "query", "schema": { "type": "string", "enum": ["error", "warning", "info"] }, "description": "Only return findings at or above this severity" },
+          { "name": "kind", "in": "query", "schema": { "type": "string" } },
+          { "name": "namespace", "in": "query", "schema": { "type": "string" } },
+          { "$ref": "#/components/parameters/labelSelector" },
+          { "$ref": "#/components/parameters/label" },
+          { "$ref": "#/components/parameters/fieldSelector" },
+          { "name": "status", "in": "query", "schema": { "type": "string" } },
+          { "$ref": "#/components/parameters/locale" }
+        ],
+        "responses": {
+          "200": { "description": "Findings", "content": { "application/json": { "schema": { "type": "array", "items": { "$ref": "#/components/schemas/Finding" } } } } },
+          "400": { "$ref": "#/components/responses/Error" }
+        }
+      }
+    },
+    "/api/v1/schema": {
+      "get": {
+        "summary": "Schema of a resource type: custom resources use their CRD, built-in resources use the cluster OpenAPI v3 or the bundled offline documentation",
+        "parameters": [
+          { "name": "apiVersion", "in": "query", "required": true, "schema": { "type": "string" }, "description": "e.g. example.com/v1" },
+          { "name": "kind", "in": "query", "required": true, "schema": { "type": "string" } },
+          { "$ref": "#/components/parameters/locale" }
+        ],
+        "responses": {
+          "200": { "description": "Simplified schema tree with field descriptions, types, defaults and allowed values", "content": { "application/json": { "schema": { "$ref": "#/components/schemas/Schema" } } } },
+          "400": { "$ref": "#/components/responses/Error" },
+          "404": { "$ref": "#/components/responses/Error" }
+        }
+      }
+    },
+    "/api/export": {
+      "get": {
+        "summary": "Export resource manifests",
+        "parameters": [
+          { "name": "format", "in": "query", "schema": { "type": "string", "enum": ["yaml", "json", "zip"], "default": "yaml" } },
+          { "name": "index", "in": "query", "schema": { "type": "string" }, "description": "Comma-separated resource indices; all resources are exported when omitted" },
+          { "name": "clean", "in": "query", "schema": { "type": "string", "enum": ["0", "1"] }, "description": "1 produces manifests that can be re-applied" }
+        ],
+        "responses": {
+          "200": { "description": "Manifest file" },
+          "400": { "$ref": "#/components/responses/Error" }
+        }
+      }
+    }
+  },
+  "components": {
+    "parameters": {
+      "labelSelector": { "name": "labelSelector", "in": "query", "schema": { "type": "string" }, "description": "Kubernetes label selector, e.g. app in (a,b),tier!=db,!canary" },
+      "label": { "name": "label", "in": "query", "schema": { "type": "string" }, "description": "Shorthand for labelSelector" },
+      "fieldSelector": { "name": "fieldSelector", "in": "query", "schema": { "type": "string" }, "description": "Field selector, e.g. status.phase=Running,spec.nodeName!=node-1" },
+      "clean": { "name": "clean", "in": "query", "schema": { "type": "string", "enum": ["0", "1"] }, "description": "0 returns full metadata; the default follows the startup flag" },
+      "locale": { "name": "locale", "in": "query", "schema": { "type": "string", "enum": ["zh", "en"] }, "description": "Language of error messages and findings; defaults to the kubectl-html-locale cookie or the -lang flag" }
+    },
+    "responses": {
+      "Error": {
+        "description": "Error",
+        "content": { "application/json": { "schema": { "type": "object", "properties": { "error": { "type": "string" } } } } }
+      }
+    },
+    "schemas": {
+      "ResourceSummary": {
+        "type": "object",
+        "properties": {
+          "index": { "type": "integer" },
+          "name": { "type": "string" },
+          "namespace": { "type": "string" },
+          "kind": { "type": "string" },
+          "apiVersion": { "type": "string" },
+          "age": { "type": "string" },
+          "status": { "type": "string" },
+          "labels": { "type": "object", "additionalProperties": { "type": "string" } },
+          "object": { "type": "object" }
+        }
+      },
+      "ResourceList": {
+        "type": "object",
+        "properties": {
+          "total": { "type": "integer" },
+          "offset": { "type": "integer" },
+          "limit": { "type": "integer" },
+          "items": { "type": "array", "items": { "$ref": "#/components/schemas/ResourceSummary" } }
+        }
+      },
+      "QueryResult": {
+        "type": "object",
+        "properties": {
+          "language": { "type": "string" },
+          "query": { "type": "string" },
+          "count": { "type": "integer" },
+          "errors": { "type": "integer", "description": "Number of resources whose evaluation failed" },
+          "firstError": { "type": "string" },
+          "rows": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "properties": {
+                "index": { "type": "integer" },
+                "kind": { "type": "string" },
+                "namespace": { "type": "string" },
+                "name": { "type": "string" },
+                "value": {}
+              }
+            }
+          }
+        }
+      },
+      "ImageInventory": {
+        "type": "object",
+        "properties": {
+          "usages": { "type": "integer" },
+          "latestCount": { "type": "integer" },
+          "noDigestCount": { "type": "integer" },
+          "mixedCount": { "type": "integer" },
+          "registries": {
+            "type": "array",
+            "items": { "type": "object", "properties": { "registry": { "type": "string" }, "images": { "type": "integer" }, "usages": { "type": "integer" } } }
+          },
+          "images": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "properties": {
+                "image": { "type": "string" },
+                "registry": { "type": "string" },
+                "repository": { "type": "string" },
+                "tag": { "type": "string" },
+                "digest": { "type": "string" },
+                "namespaces": { "type": "array", "items": { "type": "string" } },
+                "latest": { "type": "boolean" },
+                "noDigest": { "type": "boolean" },
+                "mixedTags": { "type": "boolean" },
+                "otherTags": { "type": "array", "items": { "type": "string" } },
+                "usages": {
+                  "type": "array",
+                  "items": {
+                    "type": "object",
+                    "properties": {
+                      "index": { "type": "integer" },
+                      "kind": { "type": "string" },
+                      "namespace": { "type": "string" },
+                      "name": { "type": "string" },
+                      "container": { "type": "string" },
+                      "init": { "type": "boolean" }
+                    }
+                  }
+                }
+              }
+            }
+          }
+        }
+      },
+      "Schema": {
+        "type": "object",
+        "properties": {
+          "source": { "type": "string", "enum": ["crd", "cluster", "cache", "bundled"] },
+          "version": { "type": "string", "description": "Kubernetes version the documentation belongs to" },
+          "schema": { "type": "object", "description": "Field tree: description, type, default, enum, properties, items and so on" }
+        }
+      },
+      "Finding": {
+        "type": "object",
+        "properties": {
+          "rule": { "type": "string" },
+          "severity": { "type": "string", "enum": ["error", "warning", "info"] },
+          "message": { "type": "string" },
+          "index": { "type": "integer" },
+          "kind": { "type": "string" },
+          "namespace": { "type": "string" },
+          "name": { "type": "string" }
+        }
+      },
+      "Stats": {
+        "type": "object",
+        "properties": {
+          "totalResources": { "type": "integer" },
+          "namespaceCount": { "type": "integer" },
+          "kinds": { "type": "object", "additionalProperties": { "type": "integer" } },
+          "namespaces": { "type": "object", "additionalProperties": { "type": "integer" } },
+          "statuses": { "type": "object", "additionalProperties": { "type": "integer" } },
+          "command": { "type": "string" },
+          "timestamp": { "type": "string" }
+        }
+      }
+    }
+  }
+}
+`
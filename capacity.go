@@ -0,0 +1,366 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// limit/request 比值超过该阈值的容器会被标记，可通过 -limit-ratio 调整
+var limitRequestRatioThreshold = 4.0
+
+// 容量报告统计的资源类型
+var capacityResourceNames = []string{"cpu", "memory", "ephemeral-storage"}
+
+// Kubernetes quantity 的后缀倍数
+var quantitySuffixes = map[string]float64{
+	"n":  1e-9,
+	"u":  1e-6,
+	"m":  1e-3,
+	"":   1,
+	"k":  1e3,
+	"M":  1e6,
+	"G":  1e9,
+	"T":  1e12,
+	"P":  1e15,
+	"E":  1e18,
+	"Ki": 1 << 10,
+	"Mi": 1 << 20,
+	"Gi": 1 << 30,
+	"Ti": 1 << 40,
+	"Pi": 1 << 50,
+	"Ei": 1 << 60,
+}
+
+// 解析 Kubernetes quantity 字符串，如 100m、1.5Gi、2e3，返回基本单位（核、字节）的数值
+func parseQuantity(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, errorf("空的 quantity")
+	}
+
+	// 数字部分：可选符号、数字和小数点
+	end := 0
+	if s[0] == '+' || s[0] == '-' {
+		end = 1
+	}
+	for end < len(s) && (s[end] >= '0' && s[end] <= '9' || s[end] == '.') {
+		end++
+	}
+	number, suffix := s[:end], s[end:]
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, errorf("无效的 quantity %q", s)
+	}
+
+	if multiplier, ok := quantitySuffixes[suffix]; ok {
+		return value * multiplier, nil
+	}
+	// 十进制指数形式，如 1e3、5E-3
+	if suffix[0] == 'e' || suffix[0] == 'E' {
+		exp, err := strconv.Atoi(suffix[1:])
+		if err == nil {
+			return value * math.Pow10(exp), nil
+		}
+	}
+	return 0, errorf("无效的 quantity 后缀 %q", s)
+}
+
+// 各资源类型的数量，键为 cpu、memory、ephemeral-storage
+type ResourceAmounts map[string]float64
+
+func (a ResourceAmounts) add(b ResourceAmounts, factor float64) {
+	for name, v := range b {
+		a[name] += v * factor
+	}
+}
+
+// 逐项取较大值，用于 init 容器
+func (a ResourceAmounts) max(b ResourceAmounts) {
+	for name, v := range b {
+		if v > a[name] {
+			a[name] = v
+		}
+	}
+}
+
+// 读取 resources.requests / resources.limits 中的 quantity，无法解析的值忽略
+func resourceAmounts(m map[string]interface{}) ResourceAmounts {
+	amounts := make(ResourceAmounts)
+	for _, name := range capacityResourceNames {
+		raw := stringValue(m[name])
+		if raw == "" {
+			continue
+		}
+		if v, err := parseQuantity(raw); err == nil {
+			amounts[name] = v
+		}
+	}
+	return amounts
+}
+
+// 格式化资源数量：CPU 显示为核或 m，其余显示为二进制单位
+func formatAmount(name string, v float64) string {
+	if name == "cpu" {
+		if v < 1 {
+			return strconv.FormatFloat(v*1000, 'f', -1, 64) + "m"
+		}
+		return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+	}
+	units := []string{"", "Ki", "Mi", "Gi", "Ti", "Pi"}
+	i := 0
+	for v >= 1024 && i < len(units)-1 {
+		v /= 1024
+		i++
+	}
+	return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64) + units[i]
+}
+
+// 一组资源的 requests 与 limits 汇总
+type CapacityRow struct {
+	Index       int // 关联资源的索引，-1 表示汇总行
+	Kind        string
+	Namespace   string
+	Name        string
+	Replicas    int
+	Source      string // 汇总来源：Pod 或工作负载模板
+	Requests    ResourceAmounts
+	Limits      ResourceAmounts
+	Allocatable ResourceAmounts // 仅节点行
+}
+
+// 格式化后的单元格，供模板使用
+type CapacityCell struct {
+	Request string
+	Limit   string
+	Percent string // 占节点 allocatable 的百分比
+	Over    bool   // requests 超过 allocatable
+}
+
+func (row CapacityRow) Cells() []CapacityCell {
+	cells := make([]CapacityCell, 0, len(capacityResourceNames))
+	for _, name := range capacityResourceNames {
+		cell := CapacityCell{Request: "-", Limit: "-"}
+		request, hasRequest := row.Requests[name]
+		if hasRequest {
+			cell.Request = formatAmount(name, request)
+		}
+		if v, ok := row.Limits[name]; ok {
+			cell.Limit = formatAmount(name, v)
+		}
+		if alloc := row.Allocatable[name]; alloc > 0 && hasRequest {
+			percent := request / alloc * 100
+			cell.Percent = strconv.FormatFloat(math.Round(percent), 'f', 0, 64) + "%"
+			cell.Over = percent > 100
+		}
+		cells = append(cells, cell)
+	}
+	return cells
+}
+
+// 容器资源配置问题
+type ContainerCapacityIssue struct {
+	Index     int
+	Kind      string
+	Namespace string
+	Name      string
+	Container string
+	Issues    []string
+}
+
+type CapacityReport struct {
+	Resources      []string
+	Namespaces     []CapacityRow
+	Workloads      []CapacityRow
+	Nodes          []CapacityRow
+	Issues         []ContainerCapacityIssue
+	RatioThreshold float64
+}
+
+// 单个 Pod 的有效 requests/limits：max(容器之和, 最大的 init 容器)
+func podAmounts(spec map[string]interface{}) (requests, limits ResourceAmounts) {
+	requests, limits = make(ResourceAmounts), make(ResourceAmounts)
+	initRequests, initLimits := make(ResourceAmounts), make(ResourceAmounts)
+	for _, c := range podContainers(spec) {
+		r := resourceAmounts(nestedMap(c.Spec, "resources", "requests"))
+		l := resourceAmounts(nestedMap(c.Spec, "resources", "limits"))
+		// 只设置 limits 时 requests 默认等于 limits
+		for name, v := range l {
+			if _, ok := r[name]; !ok {
+				r[name] = v
+			}
+		}
+		if c.Init {
+			initRequests.max(r)
+			initLimits.max(l)
+		} else {
+			requests.add(r, 1)
+			limits.add(l, 1)
+		}
+	}
+	requests.max(initRequests)
+	limits.max(initLimits)
+	requests.add(resourceAmounts(nestedMap(spec, "overhead")), 1)
+	return requests, limits
+}
+
+// 工作负载期望的 Pod 数
+func workloadReplicas(info ResourceInfo) int {
+	var raw interface{}
+	switch info.Kind {
+	case "Pod":
+		return 1
+	case "Deployment", "StatefulSet", "ReplicaSet":
+		raw = nestedValue(info.Parsed, "spec", "replicas")
+	case "DaemonSet":
+		raw = nestedValue(info.Parsed, "status", "desiredNumberScheduled")
+	case "Job":
+		raw = nestedValue(info.Parsed, "spec", "parallelism")
+	case "CronJob":
+		raw = nestedValue(info.Parsed, "spec", "jobTemplate", "spec", "parallelism")
+	}
+	if raw == nil {
+		return 1
+	}
+	n, err := strconv.Atoi(stringValue(raw))
+	if err != nil {
+		return 1
+	}
+	return n
+}
+
+// 检查容器是否缺少 requests/limits 或 limit/request 比值过大
+func containerCapacityIssues(c PodContainer, lang string) []string {
+	requests := resourceAmounts(nestedMap(c.Spec, "resources", "requests"))
+	limits := resourceAmounts(nestedMap(c.Spec, "resources", "limits"))
+	var issues []string
+	if requests["cpu"] == 0 && limits["cpu"] == 0 || requests["memory"] == 0 && limits["memory"] == 0 {
+		issues = append(issues, tr(lang, "未设置 requests"))
+	}
+	if limits["cpu"] == 0 || limits["memory"] == 0 {
+		issues = append(issues, tr(lang, "未设置 limits"))
+	}
+	for _, name := range capacityResourceNames {
+		if requests[name] > 0 && limits[name] > 0 {
+			ratio := limits[name] / requests[name]
+			if ratio > limitRequestRatioThreshold {
+				issues = append(issues, fmt.Sprintf("%s limit/request = %.1f", name, ratio))
+			}
+		}
+	}
+	return issues
+}
+
+// 汇总工作负载的 requests/limits，按命名空间、工作负载和节点分组
+func buildCapacityReport(infos []ResourceInfo, lang string) CapacityReport {
+	report := CapacityReport{Resources: capacityResourceNames, RatioThreshold: limitRequestRatioThreshold}
+
+	podNamespaces := make(map[string]bool)
+	for _, info := range infos {
+		if info.Kind == "Pod" {
+			podNamespaces[info.Namespace] = true
+		}
+	}
+
+	namespaces := make(map[string]*CapacityRow)
+	nodes := make(map[string]*CapacityRow)
+	for i, info := range infos {
+		if info.Kind == "Node" {
+			row := nodeRow(nodes, info.Name)
+			row.Index = i
+			row.Allocatable = resourceAmounts(nestedMap(info.Parsed, "status", "allocatable"))
+		}
+
+		spec := podSpec(info)
+		if spec == nil {
+			continue
+		}
+		requests, limits := podAmounts(spec)
+		replicas := workloadReplicas(info)
+		row := CapacityRow{
+			Index:     i,
+			Kind:      info.Kind,
+			Namespace: info.Namespace,
+			Name:      info.Name,
+			Replicas:  replicas,
+			Requests:  make(ResourceAmounts),
+			Limits:    make(ResourceAmounts),
+		}
+		row.Requests.add(requests, float64(replicas))
+		row.Limits.add(limits, float64(replicas))
+		report.Workloads = append(report.Workloads, row)
+
+		for _, c := range podContainers(spec) {
+			if issues := containerCapacityIssues(c, lang); len(issues) > 0 {
+				report.Issues = append(report.Issues, ContainerCapacityIssue{
+					Index:     i,
+					Kind:      info.Kind,
+					Namespace: info.Namespace,
+					Name:      info.Name,
+					Container: c.Name,
+					Issues:    issues,
+				})
+			}
+		}
+
+		// 命名空间汇总：已加载 Pod 时按实际 Pod 统计，否则按顶层工作负载模板估算，避免重复计算
+		var counted bool
+		if podNamespaces[info.Namespace] {
+			counted = info.Kind == "Pod" && !podFinished(info)
+		} else {
+			counted = info.Kind != "CronJob" && len(nestedSlice(info.Parsed, "metadata", "ownerReferences")) == 0
+		}
+		if counted {
+			ns, ok := namespaces[info.Namespace]
+			if !ok {
+				ns = &CapacityRow{Index: -1, Namespace: info.Namespace, Requests: make(ResourceAmounts), Limits: make(ResourceAmounts)}
+				ns.Source = tr(lang, "工作负载模板")
+				if podNamespaces[info.Namespace] {
+					ns.Source = "Pod"
+				}
+				namespaces[info.Namespace] = ns
+			}
+			ns.Replicas += replicas
+			ns.Requests.add(row.Requests, 1)
+			ns.Limits.add(row.Limits, 1)
+		}
+
+		if nodeName := nestedString(spec, "nodeName"); info.Kind == "Pod" && nodeName != "" && !podFinished(info) {
+			node := nodeRow(nodes, nodeName)
+			node.Replicas++
+			node.Requests.add(requests, 1)
+			node.Limits.add(limits, 1)
+		}
+	}
+
+	for _, ns := range namespaces {
+		report.Namespaces = append(report.Namespaces, *ns)
+	}
+	sort.Slice(report.Namespaces, func(i, j int) bool {
+		return report.Namespaces[i].Namespace < report.Namespaces[j].Namespace
+	})
+	for _, node := range nodes {
+		report.Nodes = append(report.Nodes, *node)
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool {
+		return report.Nodes[i].Name < report.Nodes[j].Name
+	})
+	return report
+}
+
+func nodeRow(nodes map[string]*CapacityRow, name string) *CapacityRow {
+	row, ok := nodes[name]
+	if !ok {
+		row = &CapacityRow{Index: -1, Kind: "Node", Name: name, Requests: make(ResourceAmounts), Limits: make(ResourceAmounts)}
+		nodes[name] = row
+	}
+	return row
+}
+
+// 已结束的 Pod 不再占用节点资源
+func podFinished(info ResourceInfo) bool {
+	phase := nestedString(info.Parsed, "status", "phase")
+	return phase == "Succeeded" || phase == "Failed"
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// 浮点结果按相对误差比较
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b))
+}
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		input string
+		want  float64
+	}{
+		{"1", 1},
+		{"0", 0},
+		{" 2 ", 2},
+		{"+3", 3},
+		{"-1", -1},
+		{"0.5", 0.5},
+		{".5", 0.5},
+		{"100m", 0.1},
+		{"1500m", 1.5},
+		{"250u", 250e-6},
+		{"10n", 10e-9},
+		{"1k", 1000},
+		{"1M", 1e6},
+		{"2G", 2e9},
+		{"1E", 1e18},
+		{"1Ki", 1024},
+		{"64Mi", 64 << 20},
+		{"1.5Gi", 1.5 * (1 << 30)},
+		{"1Ti", 1 << 40},
+		{"1e3", 1000},
+		{"1E3", 1000},
+		{"5e-3", 0.005},
+		{"12E-1", 1.2},
+		{"1.5e2", 150},
+	}
+	for _, tt := range tests {
+		got, err := parseQuantity(tt.input)
+		if err != nil {
+			t.Errorf("parseQuantity(%q) 返回错误: %v", tt.input, err)
+			continue
+		}
+		if !approxEqual(got, tt.want) {
+			t.Errorf("parseQuantity(%q) = %v, 期望 %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseQuantityErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"   ",
+		"Mi",
+		"abc",
+		"1.2.3",
+		"1Qi",
+		"1mi",
+		"1KI",
+		"1e",
+		"1ex",
+		"1 Gi",
+	}
+	for _, input := range tests {
+		if got, err := parseQuantity(input); err == nil {
+			t.Errorf("parseQuantity(%q) = %v, 期望返回错误", input, got)
+		}
+	}
+}
+
+func TestPodAmounts(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		requests ResourceAmounts
+		limits   ResourceAmounts
+	}{
+		{
+			"容器之和",
+			`{"containers": [
+				{"name": "a", "resources": {"requests": {"cpu": "100m", "memory": "64Mi"}, "limits": {"cpu": "1", "memory": "128Mi"}}},
+				{"name": "b", "resources": {"requests": {"cpu": "200m", "memory": "64Mi"}}}
+			]}`,
+			ResourceAmounts{"cpu": 0.3, "memory": 128 << 20},
+			ResourceAmounts{"cpu": 1, "memory": 128 << 20},
+		},
+		{
+			"只设置 limits 时 requests 等于 limits",
+			`{"containers": [{"name": "a", "resources": {"limits": {"cpu": "500m", "memory": "1Gi"}}}]}`,
+			ResourceAmounts{"cpu": 0.5, "memory": 1 << 30},
+			ResourceAmounts{"cpu": 0.5, "memory": 1 << 30},
+		},
+		{
+			"数字形式的 quantity",
+			`{"containers": [{"name": "a", "resources": {"requests": {"cpu": 2, "ephemeral-storage": "1e9"}}}]}`,
+			ResourceAmounts{"cpu": 2, "ephemeral-storage": 1e9},
+			ResourceAmounts{},
+		},
+		{
+			"init 容器取最大值而不是求和",
+			`{"initContainers": [
+				{"name": "migrate", "resources": {"requests": {"cpu": "1", "memory": "32Mi"}}},
+				{"name": "warmup", "resources": {"requests": {"cpu": "800m", "memory": "256Mi"}, "limits": {"memory": "512Mi"}}}
+			], "containers": [
+				{"name": "a", "resources": {"requests": {"cpu": "100m", "memory": "64Mi"}, "limits": {"memory": "128Mi"}}},
+				{"name": "b", "resources": {"requests": {"cpu": "200m", "memory": "64Mi"}, "limits": {"memory": "128Mi"}}}
+			]}`,
+			ResourceAmounts{"cpu": 1, "memory": 256 << 20},
+			ResourceAmounts{"memory": 512 << 20},
+		},
+		{
+			"init 容器小于容器之和",
+			`{"initContainers": [{"name": "init", "resources": {"requests": {"cpu": "50m"}}}],
+			  "containers": [{"name": "a", "resources": {"requests": {"cpu": "100m"}}}]}`,
+			ResourceAmounts{"cpu": 0.1},
+			ResourceAmounts{},
+		},
+		{
+			"overhead 计入 requests",
+			`{"overhead": {"cpu": "250m", "memory": "120Mi"},
+			  "containers": [{"name": "a", "resources": {"requests": {"cpu": "100m", "memory": "64Mi"}, "limits": {"cpu": "1"}}}]}`,
+			ResourceAmounts{"cpu": 0.35, "memory": 184 << 20},
+			ResourceAmounts{"cpu": 1},
+		},
+		{
+			"无法解析的值忽略",
+			`{"containers": [{"name": "a", "resources": {"requests": {"cpu": "lots", "memory": "64Mi"}}}]}`,
+			ResourceAmounts{"memory": 64 << 20},
+			ResourceAmounts{},
+		},
+	}
+	for _, tt := range tests {
+		requests, limits := podAmounts(testObject(t, tt.spec).(map[string]interface{}))
+		if !amountsEqual(requests, tt.requests) {
+			t.Errorf("%s: requests = %v, 期望 %v", tt.name, requests, tt.requests)
+		}
+		if !amountsEqual(limits, tt.limits) {
+			t.Errorf("%s: limits = %v, 期望 %v", tt.name, limits, tt.limits)
+		}
+	}
+}
+
+func amountsEqual(a, b ResourceAmounts) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, v := range b {
+		if got, ok := a[name]; !ok || !approxEqual(got, v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 证书在该天数内到期时高亮，可通过 -cert-warn-days 调整
+var certExpiryWarnDays = 30
+
+// 解析后的证书信息
+type CertificateInfo struct {
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	SANs         []string  `json:"sans,omitempty"`
+	Serial       string    `json:"serial"`
+	NotBefore    time.Time `json:"notBefore"`
+	NotAfter     time.Time `json:"notAfter"`
+	DaysLeft     int       `json:"daysLeft"`
+	Expired      bool      `json:"expired"`
+	ExpiringSoon bool      `json:"expiringSoon"`
+	IsCA         bool      `json:"isCA"`
+	SelfSigned   bool      `json:"selfSigned"`
+	Fingerprint  string    `json:"fingerprint"` // SHA-256
+}
+
+// 资源中一个包含证书的键
+type CertificateSource struct {
+	Key          string            `json:"key"`
+	Certificates []CertificateInfo `json:"certificates"`
+	ChainIssue   string            `json:"chainIssue,omitempty"` // 证书链顺序或签名问题
+	Error        string            `json:"error,omitempty"`
+}
+
+// 从 PEM 数据中解析所有证书，忽略私钥等其他块
+func parsePEMCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return certs, err
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+func certificateInfo(cert *x509.Certificate, now time.Time) CertificateInfo {
+	info := CertificateInfo{
+		Subject:    cert.Subject.String(),
+		Issuer:     cert.Issuer.String(),
+		Serial:     cert.SerialNumber.Text(16),
+		NotBefore:  cert.NotBefore,
+		NotAfter:   cert.NotAfter,
+		IsCA:       cert.IsCA,
+		SelfSigned: cert.Subject.String() == cert.Issuer.String() && cert.CheckSignatureFrom(cert) == nil,
+	}
+	info.SANs = append(info.SANs, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		info.SANs = append(info.SANs, ip.String())
+	}
+	info.SANs = append(info.SANs, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		info.SANs = append(info.SANs, uri.String())
+	}
+	sum := sha256.Sum256(cert.Raw)
+	info.Fingerprint = strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	left := cert.NotAfter.Sub(now)
+	info.DaysLeft = int(left.Hours() / 24)
+	info.Expired = left < 0
+	info.ExpiringSoon = !info.Expired && info.DaysLeft < certExpiryWarnDays
+	return info
+}
+
+// 检查证书链：每张证书应由下一张签发
+func chainIssue(certs []*x509.Certificate, lang string) string {
+	for i := 0; i+1 < len(certs); i++ {
+		if err := certs[i].CheckSignatureFrom(certs[i+1]); err != nil {
+			return tr(lang, "第 %d 张证书不是由第 %d 张签发: %v", i+1, i+2, err)
+		}
+	}
+	return ""
+}
+
+func certificateSource(key string, data []byte, now time.Time, lang string) CertificateSource {
+	source := CertificateSource{Key: key}
+	certs, err := parsePEMCertificates(data)
+	if err != nil {
+		source.Error = tr(lang, "证书解析失败: %v", err)
+	}
+	for _, cert := range certs {
+		source.Certificates = append(source.Certificates, certificateInfo(cert, now))
+	}
+	// CA 证书包中的证书彼此独立，只检查以叶子证书开头的证书链
+	if len(certs) > 1 && !certs[0].IsCA {
+		source.ChainIssue = chainIssue(certs, lang)
+	}
+	return source
+}
+
+// 提取 TLS Secret 和 ConfigMap 中的证书；Secret 的值为 base64 编码
+func resourceCertificates(info ResourceInfo, now time.Time, lang string) []CertificateSource {
+	var sources []CertificateSource
+	switch info.Kind {
+	case "Secret":
+		data := nestedMap(info.Parsed, "data")
+		isTLS := nestedString(info.Parsed, "type") == "kubernetes.io/tls"
+		for _, key := range sortedMapKeys(data) {
+			if !isTLS && !strings.HasSuffix(key, ".crt") && !strings.HasSuffix(key, ".pem") {
+				continue
+			}
+			decoded, err := base64.StdEncoding.DecodeString(stringValue(data[key]))
+			if err != nil || !strings.Contains(string(decoded), "-----BEGIN CERTIFICATE-----") {
+				continue
+			}
+			sources = append(sources, certificateSource(key, decoded, now, lang))
+		}
+	case "ConfigMap":
+		data := nestedMap(info.Parsed, "data")
+		for _, key := range sortedMapKeys(data) {
+			value := stringValue(data[key])
+			if strings.Contains(value, "-----BEGIN CERTIFICATE-----") {
+				sources = append(sources, certificateSource(key, []byte(value), now, lang))
+			}
+		}
+	}
+	return sources
+}
+
+// 证书总览中的一行
+type CertificateRow struct {
+	CertificateInfo
+	Index     int
+	Kind      string
+	Namespace string
+	Name      string
+	Key       string
+	Position  int // 在证书链中的位置，0 为叶子证书
+}
+
+// 证书链有问题或解析失败的来源
+type CertificateIssue struct {
+	Index     int
+	Kind      string
+	Namespace string
+	Name      string
+	Key       string
+	Message   string
+}
+
+type CertificateReport struct {
+	Rows          []CertificateRow
+	ExpiredCount  int
+	ExpiringCount int
+	WarnDays      int
+	Issues        []CertificateIssue
+}
+
+// 为资源附加证书信息，并生成按到期时间排序的总览
+func buildCertificateReport(infos []ResourceInfo, lang string) CertificateReport {
+	now := time.Now()
+	report := CertificateReport{WarnDays: certExpiryWarnDays}
+	for i := range infos {
+		info := &infos[i]
+		info.Certificates = resourceCertificates(*info, now, lang)
+		for _, source := range info.Certificates {
+			for pos, cert := range source.Certificates {
+				report.Rows = append(report.Rows, CertificateRow{
+					CertificateInfo: cert,
+					Index:           i,
+					Kind:            info.Kind,
+					Namespace:       info.Namespace,
+					Name:            info.Name,
+					Key:             source.Key,
+					Position:        pos,
+				})
+				if cert.Expired {
+					report.ExpiredCount++
+				} else if cert.ExpiringSoon {
+					report.ExpiringCount++
+				}
+			}
+			for _, msg := range []string{source.Error, source.ChainIssue} {
+				if msg != "" {
+					report.Issues = append(report.Issues, CertificateIssue{
+						Index:     i,
+						Kind:      info.Kind,
+						Namespace: info.Namespace,
+						Name:      info.Name,
+						Key:       source.Key,
+						Message:   msg,
+					})
+				}
+			}
+		}
+	}
+	sort.SliceStable(report.Rows, func(i, j int) bool {
+		return report.Rows[i].NotAfter.Before(report.Rows[j].NotAfter)
+	})
+	return report
+}
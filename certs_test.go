@@ -0,0 +1,241 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 测试证书及其私钥，PEM 为证书的 PEM 编码
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	PEM  string
+}
+
+// 生成测试证书，parent 为 nil 时自签名
+func newTestCert(t *testing.T, name string, isCA bool, notAfter time.Time, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("生成私钥失败: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	if !isCA {
+		template.DNSNames = []string{name}
+	}
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("生成证书失败: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("解析生成的证书失败: %v", err)
+	}
+	return &testCert{cert: cert, key: key, PEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))}
+}
+
+func (c *testCert) keyPEM(t *testing.T) string {
+	t.Helper()
+	der, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatalf("编码私钥失败: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+}
+
+func TestCertificateSource(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	year := now.Add(365 * 24 * time.Hour)
+	ca := newTestCert(t, "test-ca", true, year, nil)
+	otherCA := newTestCert(t, "other-ca", true, year, nil)
+	leaf := newTestCert(t, "web.example.com", false, year, ca)
+
+	tests := []struct {
+		name       string
+		data       string
+		subjects   []string
+		chainIssue bool
+		parseError bool
+	}{
+		{"只有叶子证书", leaf.PEM, []string{"CN=web.example.com"}, false, false},
+		{"叶子证书 + CA", leaf.PEM + ca.PEM, []string{"CN=web.example.com", "CN=test-ca"}, false, false},
+		{"忽略私钥", leaf.PEM + leaf.keyPEM(t) + ca.PEM, []string{"CN=web.example.com", "CN=test-ca"}, false, false},
+		{"签发者不匹配", leaf.PEM + otherCA.PEM, []string{"CN=web.example.com", "CN=other-ca"}, true, false},
+		{"顺序颠倒", ca.PEM + leaf.PEM, []string{"CN=test-ca", "CN=web.example.com"}, false, false},
+		{"CA 证书包", ca.PEM + otherCA.PEM, []string{"CN=test-ca", "CN=other-ca"}, false, false},
+		{"证书块损坏", leaf.PEM + "-----BEGIN CERTIFICATE-----\nAAAA\n-----END CERTIFICATE-----\n", []string{"CN=web.example.com"}, false, true},
+	}
+	for _, tt := range tests {
+		source := certificateSource("tls.crt", []byte(tt.data), now, langZH)
+		var subjects []string
+		for _, cert := range source.Certificates {
+			subjects = append(subjects, cert.Subject)
+		}
+		if strings.Join(subjects, ";") != strings.Join(tt.subjects, ";") {
+			t.Errorf("%s: 证书 = %v, 期望 %v", tt.name, subjects, tt.subjects)
+		}
+		if (source.ChainIssue != "") != tt.chainIssue {
+			t.Errorf("%s: 证书链问题 = %q, 期望有问题: %v", tt.name, source.ChainIssue, tt.chainIssue)
+		}
+		if (source.Error != "") != tt.parseError {
+			t.Errorf("%s: 解析错误 = %q, 期望有错误: %v", tt.name, source.Error, tt.parseError)
+		}
+	}
+}
+
+func TestCertificateInfoExpiry(t *testing.T) {
+	defer func(days int) { certExpiryWarnDays = days }(certExpiryWarnDays)
+	certExpiryWarnDays = 30
+
+	now := time.Now().Truncate(time.Second)
+	day := 24 * time.Hour
+	tests := []struct {
+		name     string
+		notAfter time.Time
+		daysLeft int
+		expired  bool
+		expiring bool
+	}{
+		{"一年后到期", now.Add(365 * day), 365, false, false},
+		{"刚好在提醒窗口外", now.Add(30 * day), 30, false, false},
+		{"提醒窗口内", now.Add(29*day + time.Hour), 29, false, true},
+		{"今天到期", now.Add(time.Hour), 0, false, true},
+		{"已过期", now.Add(-time.Hour), 0, true, false},
+		{"过期多天", now.Add(-10 * day), -10, true, false},
+	}
+	ca := newTestCert(t, "test-ca", true, now.Add(3650*day), nil)
+	for _, tt := range tests {
+		info := certificateInfo(newTestCert(t, "web.example.com", false, tt.notAfter, ca).cert, now)
+		if info.DaysLeft != tt.daysLeft || info.Expired != tt.expired || info.ExpiringSoon != tt.expiring {
+			t.Errorf("%s: DaysLeft=%d Expired=%v ExpiringSoon=%v, 期望 %d %v %v",
+				tt.name, info.DaysLeft, info.Expired, info.ExpiringSoon, tt.daysLeft, tt.expired, tt.expiring)
+		}
+		if info.SelfSigned || info.IsCA || len(info.SANs) != 1 || info.SANs[0] != "web.example.com" {
+			t.Errorf("%s: 叶子证书信息 = %+v", tt.name, info)
+		}
+	}
+	if info := certificateInfo(ca.cert, now); !info.SelfSigned || !info.IsCA {
+		t.Errorf("CA 证书 SelfSigned=%v IsCA=%v, 期望均为 true", info.SelfSigned, info.IsCA)
+	}
+}
+
+func TestResourceCertificates(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	year := now.Add(365 * 24 * time.Hour)
+	ca := newTestCert(t, "test-ca", true, year, nil)
+	otherCA := newTestCert(t, "other-ca", true, year, nil)
+	leaf := newTestCert(t, "web.example.com", false, year, ca)
+	b64 := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name string
+		info ResourceInfo
+		keys []string // 包含证书的键
+		n    []int    // 每个键中的证书数
+	}{
+		{
+			"TLS Secret",
+			ResourceInfo{Kind: "Secret", Parsed: map[string]interface{}{
+				"type": "kubernetes.io/tls",
+				"data": map[string]interface{}{"tls.crt": b64(leaf.PEM + ca.PEM), "tls.key": b64(leaf.keyPEM(t))},
+			}},
+			[]string{"tls.crt"}, []int{2},
+		},
+		{
+			"Opaque Secret 只检查 .crt、.pem 键",
+			ResourceInfo{Kind: "Secret", Parsed: map[string]interface{}{
+				"type": "Opaque",
+				"data": map[string]interface{}{"ca.pem": b64(ca.PEM), "ca.txt": b64(ca.PEM), "password": b64("secret")},
+			}},
+			[]string{"ca.pem"}, []int{1},
+		},
+		{
+			"ConfigMap CA 证书包",
+			ResourceInfo{Kind: "ConfigMap", Parsed: map[string]interface{}{
+				"data": map[string]interface{}{"ca-bundle.crt": ca.PEM + otherCA.PEM, "config.yaml": "a: 1"},
+			}},
+			[]string{"ca-bundle.crt"}, []int{2},
+		},
+		{
+			"其他类型不检查",
+			ResourceInfo{Kind: "Pod", Parsed: map[string]interface{}{"data": map[string]interface{}{"tls.crt": leaf.PEM}}},
+			nil, nil,
+		},
+	}
+	for _, tt := range tests {
+		sources := resourceCertificates(tt.info, now, langZH)
+		var keys []string
+		var n []int
+		for _, s := range sources {
+			keys = append(keys, s.Key)
+			n = append(n, len(s.Certificates))
+			if s.ChainIssue != "" || s.Error != "" {
+				t.Errorf("%s: %s 存在问题: %s%s", tt.name, s.Key, s.ChainIssue, s.Error)
+			}
+		}
+		if strings.Join(keys, ",") != strings.Join(tt.keys, ",") || len(n) != len(tt.n) {
+			t.Errorf("%s: 证书来源 = %v %v, 期望 %v %v", tt.name, keys, n, tt.keys, tt.n)
+			continue
+		}
+		for i := range n {
+			if n[i] != tt.n[i] {
+				t.Errorf("%s: %s 中有 %d 张证书, 期望 %d", tt.name, keys[i], n[i], tt.n[i])
+			}
+		}
+	}
+}
+
+func TestBuildCertificateReport(t *testing.T) {
+	defer func(days int) { certExpiryWarnDays = days }(certExpiryWarnDays)
+	certExpiryWarnDays = 30
+
+	now := time.Now()
+	day := 24 * time.Hour
+	ca := newTestCert(t, "test-ca", true, now.Add(3650*day), nil)
+	expired := newTestCert(t, "expired.example.com", false, now.Add(-day), ca)
+	expiring := newTestCert(t, "soon.example.com", false, now.Add(7*day), ca)
+	otherCA := newTestCert(t, "other-ca", true, now.Add(3650*day), nil)
+	infos := []ResourceInfo{
+		{Kind: "ConfigMap", Name: "bundle", Parsed: map[string]interface{}{"data": map[string]interface{}{"ca.crt": ca.PEM}}},
+		{Kind: "ConfigMap", Name: "soon", Parsed: map[string]interface{}{"data": map[string]interface{}{"tls.crt": expiring.PEM + otherCA.PEM}}},
+		{Kind: "ConfigMap", Name: "old", Parsed: map[string]interface{}{"data": map[string]interface{}{"tls.crt": expired.PEM}}},
+	}
+	report := buildCertificateReport(infos, langZH)
+	if report.ExpiredCount != 1 || report.ExpiringCount != 1 {
+		t.Errorf("已过期 %d 张、即将到期 %d 张, 期望各 1 张", report.ExpiredCount, report.ExpiringCount)
+	}
+	var names []string
+	for _, row := range report.Rows {
+		names = append(names, row.Name)
+	}
+	if got := strings.Join(names, ","); got != "old,soon,bundle,soon" {
+		t.Errorf("按到期时间排序 = %s, 期望 old,soon,bundle,soon", got)
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Name != "soon" {
+		t.Errorf("证书链问题 = %+v, 期望只有 soon", report.Issues)
+	}
+	if len(infos[1].Certificates) != 1 {
+		t.Errorf("资源上附加的证书来源 = %d, 期望 1", len(infos[1].Certificates))
+	}
+}
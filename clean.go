@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 精简视图中隐藏的服务端填充元数据字段
+var noisyMetadataFields = []string{
+	"managedFields",
+	"resourceVersion",
+	"uid",
+	"selfLink",
+}
+
+// 精简视图中隐藏的注解
+var noisyAnnotations = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+}
+
+// 字段管理者条目（来自 metadata.managedFields）
+type ManagedFieldsEntry struct {
+	Manager     string   `json:"manager"`
+	Operation   string   `json:"operation"`
+	APIVersion  string   `json:"apiVersion"`
+	Subresource string   `json:"subresource,omitempty"`
+	Time        string   `json:"time,omitempty"`
+	Fields      []string `json:"fields"`
+}
+
+// 去除噪声元数据，返回副本，不修改原资源
+func cleanResource(resource K8sResource) K8sResource {
+	if resource.Metadata == nil {
+		return resource
+	}
+
+	metadata := make(map[string]interface{}, len(resource.Metadata))
+	for k, v := range resource.Metadata {
+		metadata[k] = v
+	}
+	for _, field := range noisyMetadataFields {
+		delete(metadata, field)
+	}
+
+	if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+		kept := make(map[string]interface{}, len(annotations))
+		for k, v := range annotations {
+			kept[k] = v
+		}
+		for _, key := range noisyAnnotations {
+			delete(kept, key)
+		}
+		if len(kept) == 0 {
+			delete(metadata, "annotations")
+		} else {
+			metadata["annotations"] = kept
+		}
+	}
+
+	resource.Metadata = metadata
+	return resource
+}
+
+// 解析 managedFields，展开为"管理者 -> 字段路径"列表
+func parseManagedFields(metadata map[string]interface{}) []ManagedFieldsEntry {
+	list, ok := metadata["managedFields"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var entries []ManagedFieldsEntry
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		entry := ManagedFieldsEntry{
+			Manager:     stringValue(m["manager"]),
+			Operation:   stringValue(m["operation"]),
+			APIVersion:  stringValue(m["apiVersion"]),
+			Subresource: stringValue(m["subresource"]),
+			Time:        stringValue(m["time"]),
+		}
+		if fields, ok := m["fieldsV1"].(map[string]interface{}); ok {
+			entry.Fields = flattenFieldsV1(fields, "")
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// 将 FieldsV1 树展开为可读路径，例如 .spec.containers[name=nginx].image
+func flattenFieldsV1(node map[string]interface{}, prefix string) []string {
+	var paths []string
+
+	keys := make([]string, 0, len(node))
+	for k := range node {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if key == "." {
+			if prefix != "" {
+				paths = append(paths, prefix)
+			}
+			continue
+		}
+
+		path := prefix + fieldsV1Segment(key)
+		child, ok := node[key].(map[string]interface{})
+		if !ok || len(child) == 0 {
+			paths = append(paths, path)
+			continue
+		}
+		paths = append(paths, flattenFieldsV1(child, path)...)
+	}
+	return paths
+}
+
+// 转换单个 FieldsV1 键: f:字段, k:列表键, v:列表值, i:下标
+func fieldsV1Segment(key string) string {
+	if len(key) < 2 || key[1] != ':' {
+		return "." + key
+	}
+
+	value := key[2:]
+	switch key[0] {
+	case 'f':
+		return "." + value
+	case 'k':
+		var keyFields map[string]interface{}
+		if err := json.Unmarshal([]byte(value), &keyFields); err != nil {
+			return "[" + value + "]"
+		}
+		names := make([]string, 0, len(keyFields))
+		for name := range keyFields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		parts := make([]string, 0, len(names))
+		for _, name := range names {
+			parts = append(parts, fmt.Sprintf("%s=%v", name, keyFields[name]))
+		}
+		return "[" + strings.Join(parts, ",") + "]"
+	case 'v':
+		var v interface{}
+		if err := json.Unmarshal([]byte(value), &v); err == nil {
+			return fmt.Sprintf("[%v]", v)
+		}
+		return "[" + value + "]"
+	case 'i':
+		return "[" + value + "]"
+	default:
+		return "." + key
+	}
+}
+
+// 将任意值转换为字符串，nil 返回空串
+func stringValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// 子命令。第一个参数不是子命令时按 serve 处理，保持 kubectl html get pods 的用法
+const (
+	cmdServe      = "serve"
+	cmdExport     = "export"
+	cmdLint       = "lint"
+	cmdDiff       = "diff"
+	cmdCompletion = "completion"
+	cmdComplete   = "__complete"
+)
+
+type cliCommand struct {
+	Name  string
+	Usage string
+}
+
+// 子命令及其说明，__complete 供补全脚本调用，不在帮助中列出
+var cliCommands = []cliCommand{
+	{cmdServe, "启动 Web 界面 (默认)"},
+	{cmdExport, "将资源导出为可重新 apply 的清单"},
+	{cmdLint, "输出最佳实践检查结果，存在达到阈值的问题时以非 0 退出"},
+	{cmdDiff, "比较快照文件与集群中的当前资源"},
+	{cmdCompletion, "生成 bash、zsh、fish 补全脚本"},
+}
+
+func isSubcommand(name string) bool {
+	if name == cmdComplete {
+		return true
+	}
+	for _, c := range cliCommands {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// 命令行解析结果
+type cliConfig struct {
+	command      string
+	host         string
+	port         string
+	cleanView    bool
+	lintFailOn   string
+	fetchCRDs    bool
+	fetchOpenAPI bool
+	exportFormat string
+	outputFile   string
+	diffFrom     string
+	fromFiles    []string // 本地清单文件或目录，指定时不调用 kubectl
+	help         bool
+	kubectlArgs  []string // 用户给出的 kubectl 参数，不含追加的 -o yaml
+	args         []string // completion、__complete 的位置参数
+}
+
+// 命令行参数。Long 同时接受 --name 和旧的 -name 写法；未识别的参数原样传给 kubectl
+type cliOption struct {
+	Long     string
+	Short    string
+	Value    string   // 取值占位符，为空表示开关
+	Env      string   // 等价的环境变量，命令行参数优先
+	Usage    string   // 中文说明，输出时翻译
+	Commands []string // 适用的子命令，为空表示全部
+	Kubectl  bool     // 连接参数，以 --name=value 的形式透传给 kubectl
+	Hidden   bool     // 兼容旧版本的别名，不在帮助中列出
+	Complete []string // 补全候选值
+	set      func(cfg *cliConfig, value string) error
+}
+
+var cliOptions []cliOption
+
+func init() {
+	// 在 init 中赋值，避免 cliOptions 与引用它的 set 函数之间的初始化循环
+	cliOptions = []cliOption{
+		{Long: "host", Value: "string", Env: "KUBECTL_HTML_HOST", Usage: "服务器监听地址 (默认: localhost)", Commands: []string{cmdServe},
+			set: func(cfg *cliConfig, v string) error { cfg.host = v; return nil }},
+		{Long: "port", Short: "p", Value: "string", Env: "KUBECTL_HTML_PORT", Usage: "服务器监听端口 (默认: 8000)", Commands: []string{cmdServe},
+			set: func(cfg *cliConfig, v string) error { cfg.port = v; return nil }},
+		{Long: "no-clean", Env: "KUBECTL_HTML_NO_CLEAN", Usage: "显示或导出完整元数据 (关闭精简)", Commands: []string{cmdServe, cmdExport},
+			set: func(cfg *cliConfig, v string) error { cfg.cleanView = v != "true"; return nil }},
+		{Long: "template-dir", Value: "dir", Env: "KUBECTL_HTML_TEMPLATE_DIR", Usage: "从该目录读取 index.html、style.css、custom.css、app.js，覆盖内置页面模板", Commands: []string{cmdServe},
+			set: func(cfg *cliConfig, v string) error {
+				info, err := os.Stat(v)
+				if err != nil || !info.IsDir() {
+					return errorf("--template-dir 不是有效目录: %s", v)
+				}
+				templateDir = v
+				return nil
+			}},
+		{Long: "limit-ratio", Value: "n", Env: "KUBECTL_HTML_LIMIT_RATIO", Usage: "容器 limit/request 比值超过 n 时标记 (默认: 4)", Commands: []string{cmdServe, cmdLint},
+			set: func(cfg *cliConfig, v string) error {
+				ratio, err := strconv.ParseFloat(v, 64)
+				if err != nil || ratio <= 0 {
+					return errorf("--limit-ratio 需要一个正数: %s", v)
+				}
+				limitRequestRatioThreshold = ratio
+				return nil
+			}},
+		{Long: "target-version", Value: "version", Env: "KUBECTL_HTML_TARGET_VERSION", Usage: "升级目标 Kubernetes 版本，如 1.29，标记其中已移除的 API", Commands: []string{cmdServe, cmdLint},
+			set: func(cfg *cliConfig, v string) error {
+				if _, _, err := parseKubernetesVersion(v); err != nil {
+					return err
+				}
+				targetKubernetesVersion = strings.TrimPrefix(v, "v")
+				return nil
+			}},
+		{Long: "cert-warn-days", Value: "n", Env: "KUBECTL_HTML_CERT_WARN_DAYS", Usage: "证书在 n 天内到期时高亮 (默认: 30)", Commands: []string{cmdServe, cmdLint},
+			set: func(cfg *cliConfig, v string) error {
+				days, err := strconv.Atoi(v)
+				if err != nil || days < 0 {
+					return errorf("--cert-warn-days 需要一个非负整数: %s", v)
+				}
+				certExpiryWarnDays = days
+				return nil
+			}},
+		{Long: "fail-on", Value: "severity", Env: "KUBECTL_HTML_FAIL_ON", Usage: "导致非 0 退出码的最低严重程度: error、warning、info (默认: warning)", Commands: []string{cmdLint},
+			Complete: []string{severityError, severityWarning, severityInfo}, set: setLintFailOn},
+		{Long: "lint-fail-on", Value: "severity", Commands: []string{cmdServe, cmdLint}, Hidden: true, set: setLintFailOn},
+		{Long: "format", Value: "format", Env: "KUBECTL_HTML_FORMAT", Usage: "导出格式: yaml、json、zip (默认: yaml)", Commands: []string{cmdExport},
+			Complete: exportFormats,
+			set: func(cfg *cliConfig, v string) error {
+				if !validExportFormat(v) {
+					return errorf("不支持的导出格式: %s", v)
+				}
+				cfg.exportFormat = v
+				return nil
+			}},
+		{Long: "output-file", Value: "file", Usage: "导出到文件 (默认: 标准输出)", Commands: []string{cmdExport},
+			set: func(cfg *cliConfig, v string) error { cfg.outputFile = v; return nil }},
+		{Long: "from", Value: "file", Usage: "作为比较基准的快照文件 (export 导出的 YAML 或 JSON)", Commands: []string{cmdDiff},
+			set: func(cfg *cliConfig, v string) error { cfg.diffFrom = v; return nil }},
+		{Long: "from-file", Short: "f", Value: "file", Usage: "读取本地清单文件或目录 (- 为标准输入)，不调用 kubectl，按清单原本的 apiVersion 检查；可重复指定",
+			Commands: []string{cmdServe, cmdLint, cmdExport},
+			set:      func(cfg *cliConfig, v string) error { cfg.fromFiles = append(cfg.fromFiles, v); return nil }},
+		{Long: "no-crd-fetch", Env: "KUBECTL_HTML_NO_CRD_FETCH", Usage: "不通过 kubectl get crd 获取自定义资源的 schema",
+			set: func(cfg *cliConfig, v string) error { cfg.fetchCRDs = v != "true"; return nil }},
+		{Long: "no-openapi-fetch", Env: "KUBECTL_HTML_NO_OPENAPI_FETCH", Usage: "不获取集群 OpenAPI，字段说明只使用磁盘缓存和内置文档包", Commands: []string{cmdServe},
+			set: func(cfg *cliConfig, v string) error { cfg.fetchOpenAPI = v != "true"; return nil }},
+		{Long: "lang", Value: "lang", Env: "KUBECTL_HTML_LANG", Usage: "界面和命令行输出的语言: zh、en (默认: 取自 LANG 环境变量，否则为 zh)",
+			Complete: []string{langZH, langEN},
+			set: func(cfg *cliConfig, v string) error {
+				if normalizeLanguage(v) == "" {
+					return errorf("--lang 可选 zh、en: %s", v)
+				}
+				return nil
+			}},
+		{Long: "kubeconfig", Value: "file", Usage: "传给 kubectl 的 kubeconfig 文件 (kubectl 也读取 KUBECONFIG 环境变量)", Kubectl: true},
+		{Long: "context", Value: "name", Env: "KUBECTL_HTML_CONTEXT", Usage: "传给 kubectl 的 kubeconfig context", Kubectl: true},
+		{Long: "namespace", Short: "n", Value: "name", Env: "KUBECTL_HTML_NAMESPACE", Usage: "传给 kubectl 的命名空间", Kubectl: true},
+		{Long: "lint", Hidden: true, Commands: []string{cmdServe, cmdLint},
+			set: func(cfg *cliConfig, v string) error {
+				if v == "true" {
+					cfg.command = cmdLint
+				}
+				return nil
+			}},
+		{Long: "help", Short: "h", Usage: "显示此帮助信息",
+			set: func(cfg *cliConfig, v string) error { cfg.help = v == "true"; return nil }},
+	}
+}
+
+func setLintFailOn(cfg *cliConfig, v string) error {
+	if _, ok := severityRank[v]; !ok {
+		return errorf("--fail-on 可选 error、warning、info: %s", v)
+	}
+	cfg.lintFailOn = v
+	return nil
+}
+
+func (o *cliOption) appliesTo(command string) bool {
+	if len(o.Commands) == 0 {
+		return true
+	}
+	for _, c := range o.Commands {
+		if c == command {
+			return true
+		}
+	}
+	return false
+}
+
+// 查找参数：--name 只匹配长名称；-x 匹配短名称，-name 兼容旧版本匹配长名称
+func lookupOption(name string, long bool) *cliOption {
+	for i := range cliOptions {
+		o := &cliOptions[i]
+		if o.Long == name || (!long && o.Short != "" && o.Short == name) {
+			return o
+		}
+	}
+	return nil
+}
+
+// 拆分参数：返回参数名、是否为 -- 形式，以及 = 后的值
+func splitFlag(arg string) (name string, long bool, value string, hasValue bool) {
+	long = strings.HasPrefix(arg, "--")
+	name = strings.TrimLeft(arg, "-")
+	if i := strings.Index(name, "="); i >= 0 {
+		name, value, hasValue = name[:i], name[i+1:], true
+	}
+	return name, long, value, hasValue
+}
+
+// 从参数和环境变量中确定语言，解析其余参数前调用，使错误信息按该语言输出
+func cliLanguage(args []string) string {
+	value := os.Getenv("KUBECTL_HTML_LANG")
+	for i := 0; i < len(args) && args[i] != "--"; i++ {
+		name, _, v, hasValue := splitFlag(args[i])
+		if name != "lang" || !strings.HasPrefix(args[i], "-") {
+			continue
+		}
+		if hasValue {
+			value = v
+		} else if i+1 < len(args) {
+			value = args[i+1]
+		}
+	}
+	return value
+}
+
+// 解析命令行：[子命令] [选项] [--] <kubectl 参数>。未识别的参数和 -- 之后的全部参数都传给 kubectl；
+// 连接参数统一改写为 --name=value 再透传，避免 kubectl 把旧的 -namespace 写法解析为 -n amespace
+func parseCLI(args []string) (*cliConfig, error) {
+	cfg := &cliConfig{
+		command:      cmdServe,
+		host:         "localhost",
+		port:         "8000",
+		cleanView:    true,
+		lintFailOn:   severityWarning,
+		fetchCRDs:    true,
+		fetchOpenAPI: true,
+		exportFormat: "yaml",
+	}
+	if len(args) > 0 && isSubcommand(args[0]) {
+		cfg.command, args = args[0], args[1:]
+	}
+	if cfg.command == cmdCompletion || cfg.command == cmdComplete {
+		if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+			cfg.help = true
+		}
+		cfg.args = args
+		return cfg, nil
+	}
+
+	passed := make(map[string]bool)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			cfg.kubectlArgs = append(cfg.kubectlArgs, args[i+1:]...)
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			cfg.kubectlArgs = append(cfg.kubectlArgs, arg)
+			continue
+		}
+
+		name, long, value, hasValue := splitFlag(arg)
+		opt := lookupOption(name, long)
+		if opt == nil && !long && len(name) > 1 {
+			// -p9000、-nkube-system 这类短参数紧跟取值的写法
+			if o := lookupOption(name[:1], false); o != nil && o.Value != "" && !hasValue {
+				opt, value, hasValue = o, name[1:], true
+			}
+		}
+		if opt == nil {
+			cfg.kubectlArgs = append(cfg.kubectlArgs, arg)
+			continue
+		}
+		if !opt.appliesTo(cfg.command) {
+			return nil, errorf("参数 %s 不适用于 %s 子命令", arg, cfg.command)
+		}
+
+		if opt.Value == "" {
+			if !hasValue {
+				value = "true"
+			} else if b, err := strconv.ParseBool(value); err == nil {
+				value = strconv.FormatBool(b)
+			} else {
+				return nil, errorf("%s 需要 true 或 false: %s", "--"+opt.Long, value)
+			}
+		} else if !hasValue {
+			if i+1 >= len(args) {
+				return nil, errorf("%s 参数需要一个值", "--"+opt.Long)
+			}
+			i++
+			value = args[i]
+		}
+
+		passed[opt.Long] = true
+		if opt.Kubectl {
+			cfg.kubectlArgs = append(cfg.kubectlArgs, "--"+opt.Long+"="+value)
+			continue
+		}
+		if err := opt.set(cfg, value); err != nil {
+			return nil, err
+		}
+	}
+
+	// 环境变量只在对应参数未在命令行给出时生效
+	for i := range cliOptions {
+		o := &cliOptions[i]
+		value := os.Getenv(o.Env)
+		if o.Env == "" || value == "" || passed[o.Long] || !o.appliesTo(cfg.command) {
+			continue
+		}
+		if o.Kubectl {
+			cfg.kubectlArgs = append(cfg.kubectlArgs, "--"+o.Long+"="+value)
+			continue
+		}
+		if o.Value == "" {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, errorf("%s 需要 true 或 false: %s", o.Env, value)
+			}
+			value = strconv.FormatBool(b)
+		}
+		if err := o.set(cfg, value); err != nil {
+			return nil, errorf("环境变量 %s: %v", o.Env, err)
+		}
+	}
+	return cfg, nil
+}
+
+// 按 shell 规则引用参数，用于显示实际执行的命令
+func shellQuote(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && strings.IndexFunc(arg, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=,@%+", r))
+		}) < 0 {
+			quoted[i] = arg
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+// 输出帮助信息，指定子命令时只列出适用于该子命令的参数
+func printUsage(w io.Writer, command string, lang string) {
+	if command == cmdCompletion || command == cmdComplete {
+		fmt.Fprintln(w, tr(lang, "用法:"))
+		fmt.Fprintln(w, "  kubectl-html completion bash|zsh|fish")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, tr(lang, "示例:"))
+		fmt.Fprintln(w, "  source <(kubectl-html completion bash)")
+		fmt.Fprintln(w, "  kubectl-html completion zsh > \"${fpath[1]}/_kubectl-html\"")
+		fmt.Fprintln(w, "  kubectl-html completion fish > ~/.config/fish/completions/kubectl-html.fish")
+		return
+	}
+	fmt.Fprintln(w, tr(lang, "kubectl-html - Kubernetes 资源可视化工具"))
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, tr(lang, "用法:"))
+	fmt.Fprintln(w, "  "+tr(lang, "kubectl-html [子命令] [选项] [--] <kubectl参数...>"))
+	fmt.Fprintln(w, "  "+tr(lang, "kubectl html [子命令] [选项] [--] <kubectl参数...>  (作为 kubectl 插件)"))
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, tr(lang, "子命令:"))
+	for _, c := range cliCommands {
+		fmt.Fprintf(w, "  %-12s %s\n", c.Name, tr(lang, c.Usage))
+	}
+	fmt.Fprintln(w)
+	if command == cmdServe {
+		fmt.Fprintln(w, tr(lang, "选项:"))
+	} else {
+		fmt.Fprintln(w, tr(lang, "%s 的选项:", command))
+	}
+	for _, o := range cliOptions {
+		if o.Hidden || !o.appliesTo(command) {
+			continue
+		}
+		name := "    --" + o.Long
+		if o.Short != "" {
+			name = "-" + o.Short + ", --" + o.Long
+		}
+		if o.Value != "" {
+			name += " " + o.Value
+		}
+		usage := tr(lang, o.Usage)
+		if o.Env != "" {
+			usage += " [" + o.Env + "]"
+		}
+		fmt.Fprintf(w, "  %-30s %s\n", name, usage)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, tr(lang, "-- 之后的参数和未识别的参数都传给 kubectl，方括号中为等价的环境变量"))
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, tr(lang, "示例:"))
+	fmt.Fprintln(w, "  kubectl-html get pods")
+	fmt.Fprintln(w, "  kubectl-html serve --host 0.0.0.0 -p 9000 get deployments -A")
+	fmt.Fprintln(w, "  kubectl-html --context prod -n kube-system get po,svc,deploy")
+	fmt.Fprintln(w, "  kubectl-html lint --fail-on error --target-version 1.29 -- get deploy -A")
+	fmt.Fprintln(w, "  kubectl-html export --format zip --output-file backup.zip get cm,secret -n app")
+	fmt.Fprintln(w, "  kubectl-html diff --from backup.yaml -- get deploy -n app")
+	fmt.Fprintln(w, "  source <(kubectl-html completion bash)")
+	if command == cmdServe {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "--host:")
+		fmt.Fprintln(w, "  "+tr(lang, "localhost - 仅本机访问"))
+		fmt.Fprintln(w, "  "+tr(lang, "0.0.0.0   - 允许外部访问"))
+		fmt.Fprintln(w, "  "+tr(lang, "具体IP    - 绑定到指定网卡"))
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, tr(lang, "安全提示:"))
+		fmt.Fprintln(w, "  "+tr(lang, "使用 0.0.0.0 会允许网络中的其他设备访问"))
+		fmt.Fprintln(w, "  "+tr(lang, "请确保网络环境安全，或使用防火墙限制访问"))
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 清空所有参数对应的环境变量，避免运行环境影响解析结果
+func clearCLIEnv(t *testing.T) {
+	t.Helper()
+	for _, o := range cliOptions {
+		if o.Env != "" {
+			t.Setenv(o.Env, "")
+		}
+	}
+}
+
+func TestParseCLI(t *testing.T) {
+	clearCLIEnv(t)
+	tests := []struct {
+		args    []string
+		command string
+		port    string
+		kubectl []string
+	}{
+		{[]string{"get", "pods"}, cmdServe, "8000", []string{"get", "pods"}},
+		{[]string{"get", "pods", "-o", "wide"}, cmdServe, "8000", []string{"get", "pods", "-o", "wide"}},
+		{[]string{"-namespace", "prod", "get", "pods"}, cmdServe, "8000", []string{"--namespace=prod", "get", "pods"}},
+		{[]string{"--namespace", "prod", "get", "pods"}, cmdServe, "8000", []string{"--namespace=prod", "get", "pods"}},
+		{[]string{"-n", "prod", "get", "pods"}, cmdServe, "8000", []string{"--namespace=prod", "get", "pods"}},
+		{[]string{"-n=prod", "get", "pods"}, cmdServe, "8000", []string{"--namespace=prod", "get", "pods"}},
+		{[]string{"-nfoo", "get", "pods"}, cmdServe, "8000", []string{"--namespace=foo", "get", "pods"}},
+		{[]string{"get", "pods", "-nkube-system"}, cmdServe, "8000", []string{"get", "pods", "--namespace=kube-system"}},
+		{[]string{"-kubeconfig", "f", "-context", "x", "get", "po"}, cmdServe, "8000", []string{"--kubeconfig=f", "--context=x", "get", "po"}},
+		{[]string{"--context=x", "get", "po"}, cmdServe, "8000", []string{"--context=x", "get", "po"}},
+		{[]string{"-p9000", "get", "pods"}, cmdServe, "9000", []string{"get", "pods"}},
+		{[]string{"-p", "9000", "get", "pods"}, cmdServe, "9000", []string{"get", "pods"}},
+		{[]string{"-port", "9000", "get", "pods"}, cmdServe, "9000", []string{"get", "pods"}},
+		{[]string{"--port=9000", "get", "pods"}, cmdServe, "9000", []string{"get", "pods"}},
+		{[]string{"-p", "9000", "--", "get", "pods", "-p", "1", "-n", "x"}, cmdServe, "9000", []string{"get", "pods", "-p", "1", "-n", "x"}},
+		{[]string{"--", "--port=1"}, cmdServe, "8000", []string{"--port=1"}},
+		{[]string{"serve", "get", "pods"}, cmdServe, "8000", []string{"get", "pods"}},
+		{[]string{"lint", "-n", "prod", "get", "deploy"}, cmdLint, "8000", []string{"--namespace=prod", "get", "deploy"}},
+		{[]string{"--lint", "get", "deploy"}, cmdLint, "8000", []string{"get", "deploy"}},
+	}
+	for _, tt := range tests {
+		cfg, err := parseCLI(tt.args)
+		if err != nil {
+			t.Errorf("parseCLI(%q) 返回错误: %v", tt.args, err)
+			continue
+		}
+		if cfg.command != tt.command {
+			t.Errorf("parseCLI(%q) 子命令 = %q, 期望 %q", tt.args, cfg.command, tt.command)
+		}
+		if cfg.port != tt.port {
+			t.Errorf("parseCLI(%q) 端口 = %q, 期望 %q", tt.args, cfg.port, tt.port)
+		}
+		if !reflect.DeepEqual(cfg.kubectlArgs, tt.kubectl) {
+			t.Errorf("parseCLI(%q) kubectl 参数 = %q, 期望 %q", tt.args, cfg.kubectlArgs, tt.kubectl)
+		}
+	}
+}
+
+func TestParseCLIBoolOptions(t *testing.T) {
+	clearCLIEnv(t)
+	tests := []struct {
+		args  []string
+		clean bool
+	}{
+		{[]string{"get", "pods"}, true},
+		{[]string{"--no-clean", "get", "pods"}, false},
+		{[]string{"-no-clean", "get", "pods"}, false},
+		{[]string{"--no-clean=false", "get", "pods"}, true},
+		{[]string{"--no-clean=1", "get", "pods"}, false},
+	}
+	for _, tt := range tests {
+		cfg, err := parseCLI(tt.args)
+		if err != nil {
+			t.Errorf("parseCLI(%q) 返回错误: %v", tt.args, err)
+			continue
+		}
+		if cfg.cleanView != tt.clean {
+			t.Errorf("parseCLI(%q) cleanView = %v, 期望 %v", tt.args, cfg.cleanView, tt.clean)
+		}
+	}
+}
+
+func TestParseCLIEnv(t *testing.T) {
+	tests := []struct {
+		env     map[string]string
+		args    []string
+		port    string
+		kubectl []string
+	}{
+		{map[string]string{"KUBECTL_HTML_PORT": "7000"}, []string{"get", "pods"}, "7000", []string{"get", "pods"}},
+		{map[string]string{"KUBECTL_HTML_PORT": "7000"}, []string{"-p", "9000", "get", "pods"}, "9000", []string{"get", "pods"}},
+		{map[string]string{"KUBECTL_HTML_PORT": "7000"}, []string{"-p9000", "get", "pods"}, "9000", []string{"get", "pods"}},
+		{map[string]string{"KUBECTL_HTML_NAMESPACE": "env"}, []string{"get", "pods"}, "8000", []string{"get", "pods", "--namespace=env"}},
+		{map[string]string{"KUBECTL_HTML_NAMESPACE": "env"}, []string{"-namespace", "cli", "get", "pods"}, "8000", []string{"--namespace=cli", "get", "pods"}},
+		{map[string]string{"KUBECTL_HTML_NAMESPACE": "env"}, []string{"-ncli", "get", "pods"}, "8000", []string{"--namespace=cli", "get", "pods"}},
+		{map[string]string{"KUBECTL_HTML_CONTEXT": "env"}, []string{"--context", "cli", "get", "pods"}, "8000", []string{"--context=cli", "get", "pods"}},
+		// -- 之后的参数只传给 kubectl，不算作在命令行给出
+		{map[string]string{"KUBECTL_HTML_NAMESPACE": "env"}, []string{"--", "get", "pods", "-n", "x"}, "8000", []string{"get", "pods", "-n", "x", "--namespace=env"}},
+	}
+	for _, tt := range tests {
+		t.Run("", func(t *testing.T) {
+			clearCLIEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg, err := parseCLI(tt.args)
+			if err != nil {
+				t.Fatalf("parseCLI(%q) 返回错误: %v", tt.args, err)
+			}
+			if cfg.port != tt.port {
+				t.Errorf("%v parseCLI(%q) 端口 = %q, 期望 %q", tt.env, tt.args, cfg.port, tt.port)
+			}
+			if !reflect.DeepEqual(cfg.kubectlArgs, tt.kubectl) {
+				t.Errorf("%v parseCLI(%q) kubectl 参数 = %q, 期望 %q", tt.env, tt.args, cfg.kubectlArgs, tt.kubectl)
+			}
+		})
+	}
+}
+
+func TestParseCLIErrors(t *testing.T) {
+	clearCLIEnv(t)
+	tests := [][]string{
+		{"--port"},
+		{"get", "pods", "-n"},
+		{"--no-clean=maybe"},
+		{"export", "-p", "9000"},
+		{"lint", "--fail-on", "fatal"},
+	}
+	for _, args := range tests {
+		if _, err := parseCLI(args); err == nil {
+			t.Errorf("parseCLI(%q) 期望返回错误", args)
+		}
+	}
+}
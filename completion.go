@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// 补全指令，与 cobra 的 __complete 协议一致，kubectl 插件补全 (kubectl_complete-html) 也使用该协议
+const (
+	completeDefault    = 0
+	completeNoFileComp = 4
+	completeFilterDirs = 16
+)
+
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// 各 shell 的补全脚本，均调用 kubectl-html __complete 获取候选项
+var completionScripts = map[string]string{
+	"bash": `# kubectl-html bash 补全: source <(kubectl-html completion bash)
+_kubectl_html() {
+    local cur out directive line value
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    out=$(kubectl-html __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null)
+    directive=${out##*:}
+    COMPREPLY=()
+    while IFS= read -r line; do
+        [[ $line == :* ]] && continue
+        value=${line%%$'\t'*}
+        [[ -n $value && $value == "$cur"* ]] && COMPREPLY+=("$value")
+    done <<< "$out"
+    if [[ ${#COMPREPLY[@]} -eq 0 ]]; then
+        if (( directive & 16 )); then
+            COMPREPLY=($(compgen -d -- "$cur"))
+        elif (( (directive & 4) == 0 )); then
+            COMPREPLY=($(compgen -f -- "$cur"))
+        fi
+    fi
+    (( directive & 2 )) && compopt -o nospace
+}
+complete -o default -F _kubectl_html kubectl-html
+`,
+	"zsh": `#compdef kubectl-html
+# kubectl-html zsh 补全: source <(kubectl-html completion zsh)
+_kubectl_html() {
+    local -a lines completions
+    local out directive
+    out=$(kubectl-html __complete "${(@)words[2,CURRENT]}" 2>/dev/null)
+    lines=("${(@f)out}")
+    directive=${lines[-1]#:}
+    completions=("${(@)lines[1,-2]}")
+    completions=("${(@)completions//:/\\:}")
+    completions=("${(@)completions/$'\t'/:}")
+    if (( ${#completions} )); then
+        _describe 'kubectl-html' completions
+    elif (( directive & 16 )); then
+        _files -/
+    elif (( (directive & 4) == 0 )); then
+        _files
+    fi
+}
+compdef _kubectl_html kubectl-html
+`,
+	"fish": `# kubectl-html fish 补全: kubectl-html completion fish | source
+function __kubectl_html_complete
+    set -l args (commandline -opc)[2..-1]
+    set -l cur (commandline -ct)
+    set -l out (kubectl-html __complete $args "$cur" 2>/dev/null)
+    set -e out[-1]
+    printf '%s\n' $out
+end
+complete -c kubectl-html -a '(__kubectl_html_complete)'
+`,
+}
+
+// 补全候选项。args 为已输入的参数，最后一个是正在输入的词
+func writeCompletions(w io.Writer, args []string) {
+	cur := ""
+	if len(args) > 0 {
+		cur, args = args[len(args)-1], args[:len(args)-1]
+	}
+	command := cmdServe
+	if len(args) > 0 && isSubcommand(args[0]) {
+		command, args = args[0], args[1:]
+	}
+
+	if command == cmdCompletion {
+		for _, shell := range completionShells {
+			fmt.Fprintln(w, shell)
+		}
+		fmt.Fprintf(w, ":%d\n", completeNoFileComp)
+		return
+	}
+
+	// 上一个词是需要取值的参数时补全取值
+	afterDash := false
+	for _, arg := range args {
+		if arg == "--" {
+			afterDash = true
+		}
+	}
+	if len(args) > 0 && !afterDash {
+		name, long, _, hasValue := splitFlag(args[len(args)-1])
+		if o := lookupOption(name, long); o != nil && o.Value != "" && !hasValue && strings.HasPrefix(args[len(args)-1], "-") {
+			switch {
+			case o.Kubectl:
+				// context、namespace 的取值由 kubectl 补全
+				delegateCompletion(w, append(kubectlCompletionArgs(args), cur))
+			case len(o.Complete) > 0:
+				for _, v := range o.Complete {
+					fmt.Fprintln(w, v)
+				}
+				fmt.Fprintf(w, ":%d\n", completeNoFileComp)
+			case o.Value == "dir":
+				fmt.Fprintf(w, ":%d\n", completeFilterDirs)
+			case o.Value == "file":
+				fmt.Fprintf(w, ":%d\n", completeDefault)
+			default:
+				fmt.Fprintf(w, ":%d\n", completeNoFileComp)
+			}
+			return
+		}
+	}
+
+	if strings.HasPrefix(cur, "-") && !afterDash {
+		for _, o := range cliOptions {
+			if o.Hidden || !o.appliesTo(command) {
+				continue
+			}
+			fmt.Fprintf(w, "--%s\t%s\n", o.Long, tr(defaultLang, o.Usage))
+			if o.Short != "" {
+				fmt.Fprintf(w, "-%s\t%s\n", o.Short, tr(defaultLang, o.Usage))
+			}
+		}
+	}
+
+	kubectlArgs := kubectlCompletionArgs(args)
+	if len(kubectlArgs) == 0 && !strings.HasPrefix(cur, "-") {
+		// 第一个位置参数：子命令或 kubectl 的 get
+		if command == cmdServe && len(args) == 0 {
+			for _, c := range cliCommands {
+				fmt.Fprintf(w, "%s\t%s\n", c.Name, tr(defaultLang, c.Usage))
+			}
+		}
+		fmt.Fprintln(w, "get")
+		fmt.Fprintf(w, ":%d\n", completeNoFileComp)
+		return
+	}
+	delegateCompletion(w, append(kubectlArgs, cur))
+}
+
+// 去掉 kubectl-html 自身的参数，剩下传给 kubectl 补全的参数
+func kubectlCompletionArgs(args []string) []string {
+	var out []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			return append(out, args[i+1:]...)
+		}
+		name, long, _, hasValue := splitFlag(args[i])
+		o := lookupOption(name, long)
+		if o == nil || !strings.HasPrefix(args[i], "-") || o.Kubectl {
+			out = append(out, args[i])
+			continue
+		}
+		if o.Value != "" && !hasValue {
+			i++
+		}
+	}
+	return out
+}
+
+// 调用 kubectl __complete 补全资源类型、资源名称、kubectl 参数等，失败时不提供候选项
+func delegateCompletion(w io.Writer, args []string) {
+	cmd := exec.Command("kubectl", append([]string{"__complete"}, args...)...)
+	var outBuf bytes.Buffer
+	cmd.Stdout = &outBuf
+	if err := cmd.Run(); err != nil || outBuf.Len() == 0 {
+		fmt.Fprintf(w, ":%d\n", completeNoFileComp)
+		return
+	}
+	w.Write(outBuf.Bytes())
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 容器的一个环境变量；RefKind/RefName 为 valueFrom 引用的 ConfigMap 或 Secret
+type ContainerEnvVar struct {
+	Name    string `json:"name"`
+	Value   string `json:"value,omitempty"`
+	Source  string `json:"source,omitempty"` // valueFrom 的描述，如 ConfigMap app-config / LOG_LEVEL
+	RefKind string `json:"refKind,omitempty"`
+	RefName string `json:"refName,omitempty"`
+}
+
+// 容器的一个卷挂载及其对应的卷来源
+type ContainerMount struct {
+	MountPath string `json:"mountPath"`
+	Volume    string `json:"volume"`
+	SubPath   string `json:"subPath,omitempty"`
+	ReadOnly  bool   `json:"readOnly,omitempty"`
+	Source    string `json:"source"` // 卷类型，如 configMap、persistentVolumeClaim
+	RefKind   string `json:"refKind,omitempty"`
+	RefName   string `json:"refName,omitempty"`
+}
+
+type ContainerProbe struct {
+	Type    string `json:"type"`    // readiness、liveness、startup
+	Handler string `json:"handler"` // 如 HTTP GET :8080/healthz
+	Timing  string `json:"timing,omitempty"`
+}
+
+// 容器的终止信息
+type ContainerTermination struct {
+	Reason     string `json:"reason,omitempty"`
+	ExitCode   int    `json:"exitCode"`
+	Signal     int    `json:"signal,omitempty"`
+	Message    string `json:"message,omitempty"`
+	StartedAt  string `json:"startedAt,omitempty"`
+	FinishedAt string `json:"finishedAt,omitempty"`
+}
+
+// Pod 中一个容器的规格与运行状态
+type ContainerDetail struct {
+	Name            string                `json:"name"`
+	Init            bool                  `json:"init,omitempty"`
+	Image           string                `json:"image"`
+	ImageID         string                `json:"imageID,omitempty"`
+	State           string                `json:"state"` // waiting、running、terminated，无状态时为空
+	Reason          string                `json:"reason,omitempty"`
+	Message         string                `json:"message,omitempty"`
+	StartedAt       string                `json:"startedAt,omitempty"`
+	Ready           bool                  `json:"ready"`
+	RestartCount    int                   `json:"restartCount"`
+	Terminated      *ContainerTermination `json:"terminated,omitempty"`
+	LastTermination *ContainerTermination `json:"lastTermination,omitempty"`
+	Ports           []string              `json:"ports,omitempty"`
+	Env             []ContainerEnvVar     `json:"env,omitempty"`
+	EnvFrom         []ContainerEnvVar     `json:"envFrom,omitempty"`
+	Mounts          []ContainerMount      `json:"mounts,omitempty"`
+	Probes          []ContainerProbe      `json:"probes,omitempty"`
+}
+
+func intValue(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	}
+	return 0
+}
+
+func containerTermination(m map[string]interface{}) *ContainerTermination {
+	if m == nil {
+		return nil
+	}
+	return &ContainerTermination{
+		Reason:     nestedString(m, "reason"),
+		ExitCode:   intValue(m["exitCode"]),
+		Signal:     intValue(m["signal"]),
+		Message:    nestedString(m, "message"),
+		StartedAt:  nestedString(m, "startedAt"),
+		FinishedAt: nestedString(m, "finishedAt"),
+	}
+}
+
+// 解析 valueFrom，返回描述及引用的资源
+func envValueSource(valueFrom map[string]interface{}, lang string) (source, refKind, refName string) {
+	if ref := nestedMap(valueFrom, "configMapKeyRef"); ref != nil {
+		name := nestedString(ref, "name")
+		return "ConfigMap " + name + " / " + nestedString(ref, "key"), "ConfigMap", name
+	}
+	if ref := nestedMap(valueFrom, "secretKeyRef"); ref != nil {
+		name := nestedString(ref, "name")
+		return "Secret " + name + " / " + nestedString(ref, "key"), "Secret", name
+	}
+	if ref := nestedMap(valueFrom, "fieldRef"); ref != nil {
+		return tr(lang, "字段 %s", nestedString(ref, "fieldPath")), "", ""
+	}
+	if ref := nestedMap(valueFrom, "resourceFieldRef"); ref != nil {
+		source := tr(lang, "资源 %s", nestedString(ref, "resource"))
+		if c := nestedString(ref, "containerName"); c != "" {
+			source += " (" + c + ")"
+		}
+		return source, "", ""
+	}
+	return "valueFrom", "", ""
+}
+
+// 卷的类型及其引用的资源
+func volumeSource(volume map[string]interface{}, lang string) (source, refKind, refName string) {
+	switch {
+	case volume == nil:
+		return tr(lang, "未定义的卷"), "", ""
+	case nestedMap(volume, "configMap") != nil:
+		name := nestedString(volume, "configMap", "name")
+		return "ConfigMap " + name, "ConfigMap", name
+	case nestedMap(volume, "secret") != nil:
+		name := nestedString(volume, "secret", "secretName")
+		return "Secret " + name, "Secret", name
+	case nestedMap(volume, "persistentVolumeClaim") != nil:
+		name := nestedString(volume, "persistentVolumeClaim", "claimName")
+		return "PVC " + name, "PersistentVolumeClaim", name
+	case nestedMap(volume, "hostPath") != nil:
+		return "hostPath " + nestedString(volume, "hostPath", "path"), "", ""
+	case nestedMap(volume, "csi") != nil:
+		return "CSI " + nestedString(volume, "csi", "driver"), "", ""
+	}
+	// 其他卷类型（emptyDir、projected、downwardAPI 等）只显示类型名
+	for _, key := range sortedMapKeys(volume) {
+		if key != "name" {
+			return key, "", ""
+		}
+	}
+	return tr(lang, "未知"), "", ""
+}
+
+// 探针处理方式的简要描述
+func probeHandler(probe map[string]interface{}) string {
+	if h := nestedMap(probe, "httpGet"); h != nil {
+		scheme := nestedString(h, "scheme")
+		if scheme == "" {
+			scheme = "HTTP"
+		}
+		return fmt.Sprintf("%s GET %s:%s%s", scheme, nestedString(h, "host"), nestedString(h, "port"), nestedString(h, "path"))
+	}
+	if t := nestedMap(probe, "tcpSocket"); t != nil {
+		return "TCP :" + nestedString(t, "port")
+	}
+	if g := nestedMap(probe, "grpc"); g != nil {
+		handler := "gRPC :" + nestedString(g, "port")
+		if s := nestedString(g, "service"); s != "" {
+			handler += " " + s
+		}
+		return handler
+	}
+	if e := nestedMap(probe, "exec"); e != nil {
+		var args []string
+		for _, arg := range nestedSlice(e, "command") {
+			args = append(args, stringValue(arg))
+		}
+		return "exec " + strings.Join(args, " ")
+	}
+	return "-"
+}
+
+func probeTiming(probe map[string]interface{}) string {
+	var parts []string
+	for _, field := range []struct{ key, label string }{
+		{"initialDelaySeconds", "delay"},
+		{"periodSeconds", "period"},
+		{"timeoutSeconds", "timeout"},
+	} {
+		if v, ok := probe[field.key]; ok {
+			parts = append(parts, fmt.Sprintf("%s=%ss", field.label, stringValue(v)))
+		}
+	}
+	for _, field := range []struct{ key, label string }{
+		{"successThreshold", "success"},
+		{"failureThreshold", "failure"},
+	} {
+		if v, ok := probe[field.key]; ok {
+			parts = append(parts, fmt.Sprintf("#%s=%s", field.label, stringValue(v)))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func containerPorts(spec map[string]interface{}, lang string) []string {
+	var ports []string
+	for _, item := range nestedSlice(spec, "ports") {
+		p, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		protocol := nestedString(p, "protocol")
+		if protocol == "" {
+			protocol = "TCP"
+		}
+		port := nestedString(p, "containerPort") + "/" + protocol
+		if hostPort := nestedString(p, "hostPort"); hostPort != "" {
+			port += tr(lang, " → 宿主机 %s", hostPort)
+		}
+		if name := nestedString(p, "name"); name != "" {
+			port = name + " " + port
+		}
+		ports = append(ports, port)
+	}
+	return ports
+}
+
+// 汇总 Pod 中每个容器的规格与 status 中的运行状态
+func podContainerDetails(info ResourceInfo, lang string) []ContainerDetail {
+	spec := podSpec(info)
+	volumes := make(map[string]map[string]interface{})
+	for _, item := range nestedSlice(spec, "volumes") {
+		if v, ok := item.(map[string]interface{}); ok {
+			volumes[nestedString(v, "name")] = v
+		}
+	}
+	statuses := make(map[string]map[string]interface{})
+	for _, field := range []string{"initContainerStatuses", "containerStatuses"} {
+		for _, item := range nestedSlice(info.Parsed, "status", field) {
+			if s, ok := item.(map[string]interface{}); ok {
+				statuses[nestedString(s, "name")] = s
+			}
+		}
+	}
+
+	var details []ContainerDetail
+	for _, c := range podContainers(spec) {
+		detail := ContainerDetail{
+			Name:  c.Name,
+			Init:  c.Init,
+			Image: nestedString(c.Spec, "image"),
+			Ports: containerPorts(c.Spec, lang),
+		}
+
+		if status := statuses[c.Name]; status != nil {
+			detail.ImageID = nestedString(status, "imageID")
+			detail.Ready = status["ready"] == true
+			detail.RestartCount = intValue(status["restartCount"])
+			state := nestedMap(status, "state")
+			// state 中只会出现一个键
+			for _, name := range sortedMapKeys(state) {
+				detail.State = name
+				s := nestedMap(state, name)
+				detail.Reason = nestedString(s, "reason")
+				detail.Message = nestedString(s, "message")
+				detail.StartedAt = nestedString(s, "startedAt")
+				if name == "terminated" {
+					detail.Terminated = containerTermination(s)
+				}
+			}
+			detail.LastTermination = containerTermination(nestedMap(status, "lastState", "terminated"))
+		}
+
+		for _, item := range nestedSlice(c.Spec, "env") {
+			e, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			env := ContainerEnvVar{Name: nestedString(e, "name"), Value: nestedString(e, "value")}
+			if valueFrom := nestedMap(e, "valueFrom"); valueFrom != nil {
+				env.Source, env.RefKind, env.RefName = envValueSource(valueFrom, lang)
+			}
+			detail.Env = append(detail.Env, env)
+		}
+		for _, item := range nestedSlice(c.Spec, "envFrom") {
+			e, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			env := ContainerEnvVar{Name: nestedString(e, "prefix")}
+			if name := nestedString(e, "configMapRef", "name"); name != "" {
+				env.Source, env.RefKind, env.RefName = "ConfigMap "+name, "ConfigMap", name
+			} else if name := nestedString(e, "secretRef", "name"); name != "" {
+				env.Source, env.RefKind, env.RefName = "Secret "+name, "Secret", name
+			}
+			detail.EnvFrom = append(detail.EnvFrom, env)
+		}
+
+		for _, item := range nestedSlice(c.Spec, "volumeMounts") {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			mount := ContainerMount{
+				MountPath: nestedString(m, "mountPath"),
+				Volume:    nestedString(m, "name"),
+				SubPath:   nestedString(m, "subPath"),
+				ReadOnly:  m["readOnly"] == true,
+			}
+			mount.Source, mount.RefKind, mount.RefName = volumeSource(volumes[mount.Volume], lang)
+			detail.Mounts = append(detail.Mounts, mount)
+		}
+
+		for _, probeType := range []string{"startup", "readiness", "liveness"} {
+			if probe := nestedMap(c.Spec, probeType+"Probe"); probe != nil {
+				detail.Probes = append(detail.Probes, ContainerProbe{
+					Type:    probeType,
+					Handler: probeHandler(probe),
+					Timing:  probeTiming(probe),
+				})
+			}
+		}
+		details = append(details, detail)
+	}
+	return details
+}
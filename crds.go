@@ -0,0 +1,500 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"math"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 精简后的 OpenAPI v3 schema 节点，前端按字段路径显示说明、类型、默认值和可选值
+type SchemaNode struct {
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Default              interface{}            `json:"default,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Properties           map[string]*SchemaNode `json:"properties,omitempty"`
+	Items                *SchemaNode            `json:"items,omitempty"`
+	AdditionalProperties *SchemaNode            `json:"additionalProperties,omitempty"`
+	PreserveUnknown      bool                   `json:"preserveUnknown,omitempty"`
+	IntOrString          bool                   `json:"intOrString,omitempty"`
+	Nullable             bool                   `json:"nullable,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	PatternRegexp        *regexp.Regexp         `json:"-"` // 构建时编译的 Pattern，无效时为 nil
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	MinLength            *int                   `json:"minLength,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty"`
+	MaxItems             *int                   `json:"maxItems,omitempty"`
+}
+
+func floatPtr(v interface{}) *float64 {
+	switch n := v.(type) {
+	case int:
+		f := float64(n)
+		return &f
+	case int32:
+		f := float64(n)
+		return &f
+	case int64:
+		f := float64(n)
+		return &f
+	case uint64:
+		f := float64(n)
+		return &f
+	case float32:
+		f := float64(n)
+		return &f
+	case float64:
+		return &n
+	}
+	return nil
+}
+
+func intPtr(v interface{}) *int {
+	if f := floatPtr(v); f != nil {
+		n := int(*f)
+		return &n
+	}
+	return nil
+}
+
+// 将 schema map 转换为 SchemaNode；resolve 用于解析 $ref（CRD schema 中没有引用，传 nil 即可）
+func schemaNodeFromMap(m map[string]interface{}, resolve func(ref string) map[string]interface{}) *SchemaNode {
+	return buildSchemaNode(m, resolve, make(map[string]bool))
+}
+
+// visiting 记录当前路径上正在展开的引用，遇到自引用（如 JSONSchemaProps）时不再展开
+func buildSchemaNode(m map[string]interface{}, resolve func(ref string) map[string]interface{}, visiting map[string]bool) *SchemaNode {
+	if m == nil {
+		return nil
+	}
+	// 内置 API 的 OpenAPI 以 $ref 或 allOf: [{$ref}] 引用其他定义
+	if resolve != nil {
+		if ref := nestedString(m, "$ref"); ref != "" {
+			if visiting[ref] {
+				return &SchemaNode{Description: nestedString(m, "description"), Type: "object"}
+			}
+			if target := resolve(ref); target != nil {
+				visiting[ref] = true
+				defer delete(visiting, ref)
+				merged := make(map[string]interface{}, len(target)+1)
+				for k, v := range target {
+					merged[k] = v
+				}
+				if d := nestedString(m, "description"); d != "" {
+					merged["description"] = d
+				}
+				return buildSchemaNode(merged, resolve, visiting)
+			}
+		}
+		if allOf := nestedSlice(m, "allOf"); len(allOf) == 1 {
+			if inner, ok := allOf[0].(map[string]interface{}); ok {
+				node := buildSchemaNode(inner, resolve, visiting)
+				if node != nil {
+					copied := *node
+					if d := nestedString(m, "description"); d != "" {
+						copied.Description = d
+					}
+					if v, ok := m["default"]; ok {
+						copied.Default = v
+					}
+					return &copied
+				}
+			}
+		}
+	}
+
+	node := &SchemaNode{
+		Description:     nestedString(m, "description"),
+		Type:            nestedString(m, "type"),
+		Format:          nestedString(m, "format"),
+		Enum:            nestedSlice(m, "enum"),
+		Default:         m["default"],
+		Required:        stringSlice(nestedSlice(m, "required")),
+		PreserveUnknown: m["x-kubernetes-preserve-unknown-fields"] == true,
+		IntOrString:     m["x-kubernetes-int-or-string"] == true || nestedString(m, "format") == "int-or-string",
+		Nullable:        m["nullable"] == true,
+		Pattern:         nestedString(m, "pattern"),
+		Minimum:         floatPtr(m["minimum"]),
+		Maximum:         floatPtr(m["maximum"]),
+		MinLength:       intPtr(m["minLength"]),
+		MaxLength:       intPtr(m["maxLength"]),
+		MinItems:        intPtr(m["minItems"]),
+		MaxItems:        intPtr(m["maxItems"]),
+	}
+	if node.Pattern != "" {
+		node.PatternRegexp, _ = regexp.Compile(node.Pattern)
+	}
+	if props := nestedMap(m, "properties"); len(props) > 0 {
+		node.Properties = make(map[string]*SchemaNode, len(props))
+		for name, value := range props {
+			if child, ok := value.(map[string]interface{}); ok {
+				node.Properties[name] = buildSchemaNode(child, resolve, visiting)
+			}
+		}
+	}
+	if items := nestedMap(m, "items"); items != nil {
+		node.Items = buildSchemaNode(items, resolve, visiting)
+	}
+	switch ap := m["additionalProperties"].(type) {
+	case map[string]interface{}:
+		node.AdditionalProperties = buildSchemaNode(ap, resolve, visiting)
+	case bool:
+		if ap {
+			node.AdditionalProperties = &SchemaNode{}
+		}
+	}
+	return node
+}
+
+// 已知的 CRD schema，键为 "apiVersion kind"
+var crdSchemas = make(map[string]*SchemaNode)
+
+func schemaKey(apiVersion, kind string) string {
+	return apiVersion + " " + kind
+}
+
+// 从 CRD 对象中注册每个版本的 schema，返回注册的数量
+func registerCRDSchemas(resources []K8sResource) int {
+	count := 0
+	for _, resource := range resources {
+		if resource.Kind != "CustomResourceDefinition" {
+			continue
+		}
+		spec, ok := resource.Spec.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		group := nestedString(spec, "group")
+		kind := nestedString(spec, "names", "kind")
+		// apiextensions.k8s.io/v1beta1 的 CRD 所有版本共用 spec.validation
+		shared := nestedMap(spec, "validation", "openAPIV3Schema")
+		versions := nestedSlice(spec, "versions")
+		if len(versions) == 0 && nestedString(spec, "version") != "" {
+			versions = []interface{}{map[string]interface{}{"name": nestedString(spec, "version")}}
+		}
+		for _, item := range versions {
+			v, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			schema := nestedMap(v, "schema", "openAPIV3Schema")
+			if schema == nil {
+				schema = shared
+			}
+			if schema == nil {
+				continue
+			}
+			apiVersion := group + "/" + nestedString(v, "name")
+			node := schemaNodeFromMap(schema, nil)
+			logInvalidSchemaPatterns(apiVersion, kind, node, "")
+			crdSchemas[schemaKey(apiVersion, kind)] = node
+			count++
+		}
+	}
+	return count
+}
+
+// 注册时报告无法编译的 pattern，校验时跳过这些 pattern
+func logInvalidSchemaPatterns(apiVersion, kind string, node *SchemaNode, path string) {
+	if node == nil {
+		return
+	}
+	if node.Pattern != "" && node.PatternRegexp == nil {
+		_, err := regexp.Compile(node.Pattern)
+		log.Printf("⚠️  Warning: Invalid pattern in %s %s schema at %q, skipping: %v", apiVersion, kind, path, err)
+	}
+	names := make([]string, 0, len(node.Properties))
+	for name := range node.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		logInvalidSchemaPatterns(apiVersion, kind, node.Properties[name], joinSchemaPath(path, name))
+	}
+	logInvalidSchemaPatterns(apiVersion, kind, node.Items, path+"[*]")
+	logInvalidSchemaPatterns(apiVersion, kind, node.AdditionalProperties, joinSchemaPath(path, "*"))
+}
+
+// 内置 API 组，这些组的资源没有 CRD
+var builtinAPIGroups = map[string]bool{
+	"": true, "apps": true, "batch": true, "autoscaling": true, "policy": true, "extensions": true,
+	"networking.k8s.io": true, "rbac.authorization.k8s.io": true, "storage.k8s.io": true,
+	"apiextensions.k8s.io": true, "admissionregistration.k8s.io": true, "apiregistration.k8s.io": true,
+	"authentication.k8s.io": true, "authorization.k8s.io": true, "certificates.k8s.io": true,
+	"coordination.k8s.io": true, "discovery.k8s.io": true, "events.k8s.io": true,
+	"flowcontrol.apiserver.k8s.io": true, "node.k8s.io": true, "scheduling.k8s.io": true,
+	"resource.k8s.io": true, "internal.apiserver.k8s.io": true, "storagemigration.k8s.io": true,
+	"metrics.k8s.io": true,
+}
+
+func apiGroup(apiVersion string) string {
+	if i := strings.LastIndex(apiVersion, "/"); i >= 0 {
+		return apiVersion[:i]
+	}
+	return ""
+}
+
+// 是否有自定义资源缺少对应的 CRD schema
+func missingCRDSchemas(resources []K8sResource) bool {
+	for _, resource := range resources {
+		if resource.Kind == "List" || builtinAPIGroups[apiGroup(resource.APIVersion)] {
+			continue
+		}
+		if _, ok := crdSchemas[schemaKey(resource.APIVersion, resource.Kind)]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// kubectl 的全局连接和认证参数，额外的 kubectl 调用需要与主命令连接同一个集群、使用同一身份
+var kubectlConnectionFlags = []string{
+	"--kubeconfig", "--context", "--cluster", "--user", "--server", "-s", "--token",
+	"--as", "--as-group", "--as-uid", "--username", "--password",
+	"--certificate-authority", "--client-certificate", "--client-key",
+	"--tls-server-name", "--request-timeout", "--cache-dir",
+}
+
+// 不带取值的布尔参数，只接受 --name 或 --name=value 写法，不能吞掉下一个参数
+var kubectlConnectionBoolFlags = []string{"--insecure-skip-tls-verify", "--disable-compression"}
+
+// 从 kubectl 参数中提取连接集群所需的参数，供额外的 kubectl 调用复用
+func kubectlConnectionArgs(args []string) []string {
+	var out []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			break
+		}
+		for _, flag := range kubectlConnectionBoolFlags {
+			if args[i] == flag || strings.HasPrefix(args[i], flag+"=") {
+				out = append(out, args[i])
+				break
+			}
+		}
+		for _, flag := range kubectlConnectionFlags {
+			if args[i] == flag && i+1 < len(args) {
+				out = append(out, args[i], args[i+1])
+				i++
+				break
+			}
+			if strings.HasPrefix(args[i], flag+"=") {
+				out = append(out, args[i])
+				break
+			}
+		}
+	}
+	return out
+}
+
+// 执行额外的 kubectl 命令，错误信息中附带 stderr
+func runKubectl(args ...string) ([]byte, error) {
+	cmd := exec.Command("kubectl", args...)
+	var outBuf, errBuf bytes.Buffer
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%v %s", err, strings.TrimSpace(errBuf.String()))
+	}
+	return outBuf.Bytes(), nil
+}
+
+// 加载资源中的 CRD schema；自定义资源的 CRD 不在其中时通过 kubectl get crd 补充获取
+func loadCRDSchemas(resources []K8sResource, kubectlArgs []string, fetch bool) {
+	if n := registerCRDSchemas(resources); n > 0 {
+		log.Printf("📐 Loaded %d CRD schemas from resources", n)
+	}
+	if !fetch || !missingCRDSchemas(resources) {
+		return
+	}
+	out, err := runKubectl(append([]string{"get", "crd", "-o", "yaml"}, kubectlConnectionArgs(kubectlArgs)...)...)
+	if err != nil {
+		log.Printf("⚠️  Warning: Failed to fetch CRDs for schema validation: %v", err)
+		return
+	}
+	crds, err := parseKubernetesYAML(string(out))
+	if err != nil {
+		log.Printf("⚠️  Warning: Failed to parse CRDs: %v", err)
+		return
+	}
+	log.Printf("📐 Loaded %d CRD schemas from cluster", registerCRDSchemas(crds))
+}
+
+// schema 校验发现的一个问题，Path 与前端结构化视图中的字段路径一致（如 spec.items[0].name）
+type SchemaIssue struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func joinSchemaPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func schemaTypeOf(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int32, int64, uint64:
+		return "integer"
+	case float32:
+		if f := float64(v); f == math.Trunc(f) {
+			return "integer"
+		}
+		return "number"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case nil:
+		return "null"
+	}
+	// 无法识别的 Go 类型不能当作 null 放过，按类型不符报告
+	return "unknown"
+}
+
+// 按 schema 校验值，问题按 lang 语言描述后追加到 issues
+func validateSchema(value interface{}, node *SchemaNode, path, lang string, issues *[]SchemaIssue) {
+	if node == nil || node.PreserveUnknown && node.Type == "" {
+		return
+	}
+	add := func(format string, args ...interface{}) {
+		*issues = append(*issues, SchemaIssue{Path: path, Message: tr(lang, format, args...)})
+	}
+	actual := schemaTypeOf(value)
+	if actual == "null" {
+		if !node.Nullable && node.Type != "" {
+			add("值为 null，应为 %s", node.Type)
+		}
+		return
+	}
+
+	switch {
+	case node.IntOrString:
+		if actual != "integer" && actual != "string" {
+			add("类型为 %s，应为整数或字符串", actual)
+			return
+		}
+	case node.Type == "number":
+		if actual != "integer" && actual != "number" {
+			add("类型为 %s，应为 number", actual)
+			return
+		}
+	case node.Type != "" && node.Type != actual:
+		add("类型为 %s，应为 %s", actual, node.Type)
+		return
+	}
+
+	if len(node.Enum) > 0 {
+		found := false
+		for _, e := range node.Enum {
+			if fmt.Sprint(e) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			add("值 %v 不在可选值 %v 中", value, node.Enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		if node.PatternRegexp != nil && !node.PatternRegexp.MatchString(v) {
+			add("值 %q 不匹配模式 %s", v, node.Pattern)
+		}
+		length := len([]rune(v))
+		if node.MinLength != nil && length < *node.MinLength {
+			add("长度 %d 小于最小长度 %d", length, *node.MinLength)
+		}
+		if node.MaxLength != nil && length > *node.MaxLength {
+			add("长度 %d 超过最大长度 %d", length, *node.MaxLength)
+		}
+	case []interface{}:
+		if node.MinItems != nil && len(v) < *node.MinItems {
+			add("元素个数 %d 小于 %d", len(v), *node.MinItems)
+		}
+		if node.MaxItems != nil && len(v) > *node.MaxItems {
+			add("元素个数 %d 超过 %d", len(v), *node.MaxItems)
+		}
+		for i, item := range v {
+			validateSchema(item, node.Items, fmt.Sprintf("%s[%d]", path, i), lang, issues)
+		}
+	case map[string]interface{}:
+		for _, name := range node.Required {
+			if _, ok := v[name]; !ok {
+				*issues = append(*issues, SchemaIssue{Path: joinSchemaPath(path, name), Message: tr(lang, "缺少必填字段 %s", name)})
+			}
+		}
+		for _, key := range sortedMapKeys(v) {
+			childPath := joinSchemaPath(path, key)
+			if child, ok := node.Properties[key]; ok {
+				validateSchema(v[key], child, childPath, lang, issues)
+			} else if node.AdditionalProperties != nil {
+				validateSchema(v[key], node.AdditionalProperties, childPath, lang, issues)
+			} else if len(node.Properties) > 0 && !node.PreserveUnknown {
+				*issues = append(*issues, SchemaIssue{Path: childPath, Message: tr(lang, "schema 中没有该字段")})
+			}
+		}
+	default:
+		if f := floatPtr(value); f != nil {
+			if node.Minimum != nil && *f < *node.Minimum {
+				add("值 %v 小于最小值 %v", value, *node.Minimum)
+			}
+			if node.Maximum != nil && *f > *node.Maximum {
+				add("值 %v 超过最大值 %v", value, *node.Maximum)
+			}
+		}
+	}
+}
+
+// 按 CRD schema 校验自定义资源；metadata 由 API server 统一校验，这里跳过
+func validateCustomResource(info ResourceInfo, lang string) []SchemaIssue {
+	schema := crdSchemas[schemaKey(info.APIVersion, info.Kind)]
+	if schema == nil {
+		return nil
+	}
+	var issues []SchemaIssue
+	for _, name := range schema.Required {
+		if _, ok := info.Parsed[name]; !ok {
+			issues = append(issues, SchemaIssue{Path: name, Message: tr(lang, "缺少必填字段 %s", name)})
+		}
+	}
+	for _, key := range sortedMapKeys(info.Parsed) {
+		if key == "apiVersion" || key == "kind" || key == "metadata" {
+			continue
+		}
+		if child, ok := schema.Properties[key]; ok {
+			validateSchema(info.Parsed[key], child, key, lang, &issues)
+		} else if len(schema.Properties) > 0 && !schema.PreserveUnknown {
+			issues = append(issues, SchemaIssue{Path: key, Message: tr(lang, "schema 中没有该字段")})
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Path < issues[j].Path })
+	return issues
+}
+
+func checkCRDSchema(ctx *lintContext, info ResourceInfo) []string {
+	var problems []string
+	for _, issue := range info.SchemaIssues {
+		problems = append(problems, issue.Path+": "+issue.Message)
+	}
+	return problems
+}
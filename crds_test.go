@@ -0,0 +1,56 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKubectlConnectionArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"get", "pods", "--namespace=prod"}, nil},
+		{[]string{"get", "pods", "--context", "x", "--kubeconfig=f"}, []string{"--context", "x", "--kubeconfig=f"}},
+		{[]string{"get", "pods", "-s", "https://k8s:6443", "--token=t"}, []string{"-s", "https://k8s:6443", "--token=t"}},
+		{[]string{"--as", "admin", "--as-group=system:masters", "get", "pods"}, []string{"--as", "admin", "--as-group=system:masters"}},
+		{[]string{"--certificate-authority", "ca.crt", "--tls-server-name=api", "get", "pods"}, []string{"--certificate-authority", "ca.crt", "--tls-server-name=api"}},
+		{[]string{"--request-timeout=5s", "get", "pods"}, []string{"--request-timeout=5s"}},
+		{[]string{"--insecure-skip-tls-verify", "get", "pods"}, []string{"--insecure-skip-tls-verify"}},
+		{[]string{"--insecure-skip-tls-verify=false", "get", "pods"}, []string{"--insecure-skip-tls-verify=false"}},
+		{[]string{"get", "pods", "--context"}, nil},
+		{[]string{"exec", "web", "--context=x", "--", "sh", "--user", "root"}, []string{"--context=x"}},
+	}
+	for _, tt := range tests {
+		if got := kubectlConnectionArgs(tt.args); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("kubectlConnectionArgs(%q) = %q, 期望 %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestSchemaTypeOf(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{nil, "null"},
+		{"x", "string"},
+		{true, "boolean"},
+		{3, "integer"},
+		{int32(3), "integer"},
+		{int64(3), "integer"},
+		{uint64(1 << 63), "integer"},
+		{float32(2), "integer"},
+		{float32(2.5), "number"},
+		{2.0, "integer"},
+		{2.5, "number"},
+		{[]interface{}{}, "array"},
+		{map[string]interface{}{}, "object"},
+		{struct{}{}, "unknown"},
+	}
+	for _, tt := range tests {
+		if got := schemaTypeOf(tt.value); got != tt.want {
+			t.Errorf("schemaTypeOf(%#v) = %q, 期望 %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// 升级目标版本，如 1.29，由 -target-version 指定；为空时列出所有已弃用的 API
+var targetKubernetesVersion string
+
+// 一条 API 弃用记录
+type APIDeprecation struct {
+	APIVersion   string
+	Kind         string
+	DeprecatedIn string
+	RemovedIn    string
+	Replacement  string // 替代的 apiVersion，为空表示没有直接替代
+}
+
+// 内置的 API 弃用表 https://kubernetes.io/docs/reference/using-api/deprecation-guide/
+var apiDeprecations = []APIDeprecation{
+	{"extensions/v1beta1", "Deployment", "1.9", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "DaemonSet", "1.9", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "ReplicaSet", "1.9", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "NetworkPolicy", "1.9", "1.16", "networking.k8s.io/v1"},
+	{"extensions/v1beta1", "PodSecurityPolicy", "1.10", "1.16", "policy/v1beta1"},
+	{"extensions/v1beta1", "Ingress", "1.14", "1.22", "networking.k8s.io/v1"},
+	{"apps/v1beta1", "Deployment", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta1", "StatefulSet", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "Deployment", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "StatefulSet", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "DaemonSet", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "ReplicaSet", "1.9", "1.16", "apps/v1"},
+	{"networking.k8s.io/v1beta1", "Ingress", "1.19", "1.22", "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", "IngressClass", "1.19", "1.22", "networking.k8s.io/v1"},
+	{"apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", "1.16", "1.22", "apiextensions.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", "MutatingWebhookConfiguration", "1.16", "1.22", "admissionregistration.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", "ValidatingWebhookConfiguration", "1.16", "1.22", "admissionregistration.k8s.io/v1"},
+	{"apiregistration.k8s.io/v1beta1", "APIService", "1.19", "1.22", "apiregistration.k8s.io/v1"},
+	{"authentication.k8s.io/v1beta1", "TokenReview", "1.19", "1.22", "authentication.k8s.io/v1"},
+	{"authorization.k8s.io/v1beta1", "SubjectAccessReview", "1.19", "1.22", "authorization.k8s.io/v1"},
+	{"authorization.k8s.io/v1beta1", "LocalSubjectAccessReview", "1.19", "1.22", "authorization.k8s.io/v1"},
+	{"authorization.k8s.io/v1beta1", "SelfSubjectAccessReview", "1.19", "1.22", "authorization.k8s.io/v1"},
+	{"certificates.k8s.io/v1beta1", "CertificateSigningRequest", "1.19", "1.22", "certificates.k8s.io/v1"},
+	{"coordination.k8s.io/v1beta1", "Lease", "1.19", "1.22", "coordination.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRole", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRoleBinding", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "Role", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "RoleBinding", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"scheduling.k8s.io/v1beta1", "PriorityClass", "1.14", "1.22", "scheduling.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSIDriver", "1.19", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSINode", "1.17", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "StorageClass", "1.19", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "VolumeAttachment", "1.19", "1.22", "storage.k8s.io/v1"},
+	{"batch/v1beta1", "CronJob", "1.21", "1.25", "batch/v1"},
+	{"discovery.k8s.io/v1beta1", "EndpointSlice", "1.21", "1.25", "discovery.k8s.io/v1"},
+	{"events.k8s.io/v1beta1", "Event", "1.19", "1.25", "events.k8s.io/v1"},
+	{"autoscaling/v2beta1", "HorizontalPodAutoscaler", "1.22", "1.25", "autoscaling/v2"},
+	{"policy/v1beta1", "PodDisruptionBudget", "1.21", "1.25", "policy/v1"},
+	{"policy/v1beta1", "PodSecurityPolicy", "1.21", "1.25", ""},
+	{"node.k8s.io/v1beta1", "RuntimeClass", "1.20", "1.25", "node.k8s.io/v1"},
+	{"autoscaling/v2beta2", "HorizontalPodAutoscaler", "1.23", "1.26", "autoscaling/v2"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "FlowSchema", "1.23", "1.26", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "PriorityLevelConfiguration", "1.23", "1.26", "flowcontrol.apiserver.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSIStorageCapacity", "1.24", "1.27", "storage.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "FlowSchema", "1.26", "1.29", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "PriorityLevelConfiguration", "1.26", "1.29", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "FlowSchema", "1.29", "1.32", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "PriorityLevelConfiguration", "1.29", "1.32", "flowcontrol.apiserver.k8s.io/v1"},
+}
+
+// 解析 1.29、v1.29.3 形式的版本号，返回 (major, minor)
+func parseKubernetesVersion(v string) (int, int, error) {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(v), "v"), ".")
+	if len(parts) < 2 {
+		return 0, 0, errorf("无效的 Kubernetes 版本: %s（格式如 1.29）", v)
+	}
+	major, err1 := strconv.Atoi(parts[0])
+	minor, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil {
+		return 0, 0, errorf("无效的 Kubernetes 版本: %s（格式如 1.29）", v)
+	}
+	return major, minor, nil
+}
+
+// 版本 a 是否不低于 b，两者均为内置表或已校验过的版本号
+func versionAtLeast(a, b string) bool {
+	aMajor, aMinor, _ := parseKubernetesVersion(a)
+	bMajor, bMinor, _ := parseKubernetesVersion(b)
+	return aMajor > bMajor || aMajor == bMajor && aMinor >= bMinor
+}
+
+func findDeprecation(apiVersion, kind string) (APIDeprecation, bool) {
+	for _, d := range apiDeprecations {
+		if d.APIVersion == apiVersion && d.Kind == kind {
+			return d, true
+		}
+	}
+	return APIDeprecation{}, false
+}
+
+// 资源中使用已弃用 API 的一处
+type DeprecatedAPIUsage struct {
+	APIDeprecation
+	Source  string // 对象本身或 last-applied 注解，为未翻译的中文消息
+	Removed bool   // 在目标版本中已移除
+}
+
+func (u DeprecatedAPIUsage) Message(lang string) string {
+	var msg string
+	if u.Removed {
+		msg = tr(lang, "%s %s 已在 %s 中移除", u.APIVersion, u.Kind, u.RemovedIn)
+	} else {
+		msg = tr(lang, "%s %s 自 %s 起弃用，将在 %s 中移除", u.APIVersion, u.Kind, u.DeprecatedIn, u.RemovedIn)
+	}
+	if u.Replacement != "" {
+		msg += tr(lang, "，请改用 %s", u.Replacement)
+	} else {
+		msg += tr(lang, "，没有直接替代的 API")
+	}
+	return msg + tr(lang, "（来源: %s）", tr(lang, u.Source))
+}
+
+// 检查资源本身及 last-applied-configuration 注解中使用的 API 版本；
+// 从集群读取的对象会以首选版本返回，注解中保留了清单原本的 apiVersion
+func deprecatedAPIUsages(info ResourceInfo, target string) []DeprecatedAPIUsage {
+	type source struct{ apiVersion, kind, name string }
+	sources := []source{{info.APIVersion, info.Kind, "对象"}}
+	if raw := resourceAnnotations(info)["kubectl.kubernetes.io/last-applied-configuration"]; raw != "" {
+		var applied struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+		}
+		if err := json.Unmarshal([]byte(raw), &applied); err == nil && applied.APIVersion != info.APIVersion {
+			sources = append(sources, source{applied.APIVersion, applied.Kind, "last-applied 注解"})
+		}
+	}
+
+	var usages []DeprecatedAPIUsage
+	for _, s := range sources {
+		d, ok := findDeprecation(s.apiVersion, s.kind)
+		if !ok {
+			continue
+		}
+		usage := DeprecatedAPIUsage{APIDeprecation: d, Source: s.name}
+		if target != "" {
+			if !versionAtLeast(target, d.DeprecatedIn) {
+				continue
+			}
+			usage.Removed = versionAtLeast(target, d.RemovedIn)
+		}
+		usages = append(usages, usage)
+	}
+	return usages
+}
+
+// 页面展示用的弃用 API 报告
+type DeprecationRow struct {
+	DeprecatedAPIUsage
+	Index     int
+	Namespace string
+	Name      string
+}
+
+type DeprecationReport struct {
+	TargetVersion string
+	Rows          []DeprecationRow
+	RemovedCount  int
+	Table         []APIDeprecation
+}
+
+func buildDeprecationReport(infos []ResourceInfo) DeprecationReport {
+	report := DeprecationReport{TargetVersion: targetKubernetesVersion, Table: apiDeprecations}
+	for i, info := range infos {
+		for _, usage := range deprecatedAPIUsages(info, targetKubernetesVersion) {
+			report.Rows = append(report.Rows, DeprecationRow{DeprecatedAPIUsage: usage, Index: i, Namespace: info.Namespace, Name: info.Name})
+			if usage.Removed {
+				report.RemovedCount++
+			}
+		}
+	}
+	return report
+}
+
+// 检查规则：目标版本中已移除的 API
+func checkRemovedAPI(ctx *lintContext, info ResourceInfo) []string {
+	var problems []string
+	for _, usage := range deprecatedAPIUsages(info, targetKubernetesVersion) {
+		if usage.Removed {
+			problems = append(problems, usage.Message(ctx.lang))
+		}
+	}
+	return problems
+}
+
+// 检查规则：已弃用但在目标版本中仍可用的 API
+func checkDeprecatedAPI(ctx *lintContext, info ResourceInfo) []string {
+	var problems []string
+	for _, usage := range deprecatedAPIUsages(info, targetKubernetesVersion) {
+		if !usage.Removed {
+			problems = append(problems, usage.Message(ctx.lang))
+		}
+	}
+	return problems
+}
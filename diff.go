@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// diff 输出中每处差异前后保留的上下文行数
+const diffContextLines = 3
+
+// 资源在 diff 中的标识：忽略 API 版本，v1beta1 与 v1 的同一资源视为同一个
+func diffKey(info ResourceInfo) string {
+	return apiGroup(info.APIVersion) + "/" + info.Kind + "/" + info.Namespace + "/" + info.Name
+}
+
+// 资源显示名：Kind namespace/name，集群级资源省略命名空间
+func diffName(info ResourceInfo) string {
+	if info.Namespace == "" {
+		return info.Kind + " " + info.Name
+	}
+	return info.Kind + " " + info.Namespace + "/" + info.Name
+}
+
+// 按可重新 apply 的形式比较，status 和服务端填充的元数据不计入差异
+func diffYAMLLines(info ResourceInfo) []string {
+	out, err := yaml.Marshal(exportReadyObject(info.Parsed))
+	if err != nil {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+}
+
+type lineEdit struct {
+	op   byte // ' ' 相同，'-' 仅在旧版本，'+' 仅在新版本
+	text string
+}
+
+// 逐行比较，去掉相同的首尾后对中间部分求最长公共子序列
+func diffLines(a, b []string) []lineEdit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	x, y := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i][j] 为 x[i:] 与 y[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	edits := make([]lineEdit, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		edits = append(edits, lineEdit{' ', line})
+	}
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			edits = append(edits, lineEdit{' ', x[i]})
+			i++
+			j++
+		case j >= len(y) || (i < len(x) && lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, lineEdit{'-', x[i]})
+			i++
+		default:
+			edits = append(edits, lineEdit{'+', y[j]})
+			j++
+		}
+	}
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, lineEdit{' ', line})
+	}
+	return edits
+}
+
+// 生成 unified diff 格式的差异块，间隔不超过两倍上下文的变更合并为一块
+func unifiedDiff(edits []lineEdit, context int) []string {
+	n := len(edits)
+	// aLines[k]、bLines[k] 为 edits[:k] 中旧、新版本的行数
+	aLines, bLines := make([]int, n+1), make([]int, n+1)
+	for k, e := range edits {
+		aLines[k+1], bLines[k+1] = aLines[k], bLines[k]
+		if e.op != '+' {
+			aLines[k+1]++
+		}
+		if e.op != '-' {
+			bLines[k+1]++
+		}
+	}
+
+	var out []string
+	for i := 0; i < n; {
+		for i < n && edits[i].op == ' ' {
+			i++
+		}
+		if i >= n {
+			break
+		}
+		end := i
+		for {
+			for end < n && edits[end].op != ' ' {
+				end++
+			}
+			next := end
+			for next < n && edits[next].op == ' ' {
+				next++
+			}
+			if next < n && next-end <= 2*context {
+				end = next
+				continue
+			}
+			break
+		}
+		start, stop := max(i-context, 0), min(end+context, n)
+
+		aStart, aCount := aLines[start]+1, aLines[stop]-aLines[start]
+		bStart, bCount := bLines[start]+1, bLines[stop]-bLines[start]
+		if aCount == 0 {
+			aStart--
+		}
+		if bCount == 0 {
+			bStart--
+		}
+		out = append(out, fmt.Sprintf("@@ -%d,%d +%d,%d @@", aStart, aCount, bStart, bCount))
+		for _, e := range edits[start:stop] {
+			out = append(out, string(e.op)+e.text)
+		}
+		i = stop
+	}
+	return out
+}
+
+// 比较快照与当前资源并输出差异，返回有差异的资源数
+func printResourceDiff(w io.Writer, fromName, toName string, before, after []ResourceInfo, lang string) int {
+	old := make(map[string]ResourceInfo, len(before))
+	for _, info := range before {
+		old[diffKey(info)] = info
+	}
+	current := make(map[string]ResourceInfo, len(after))
+	var keys []string
+	for _, info := range after {
+		current[diffKey(info)] = info
+		keys = append(keys, diffKey(info))
+	}
+	for key := range old {
+		if _, ok := current[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", fromName, toName)
+	var added, removed, changed int
+	for _, key := range keys {
+		prev, inOld := old[key]
+		info, inCurrent := current[key]
+		switch {
+		case !inOld:
+			added++
+			fmt.Fprintf(w, "+ %s\n", diffName(info))
+		case !inCurrent:
+			removed++
+			fmt.Fprintf(w, "- %s\n", diffName(prev))
+		default:
+			hunks := unifiedDiff(diffLines(diffYAMLLines(prev), diffYAMLLines(info)), diffContextLines)
+			if len(hunks) == 0 {
+				continue
+			}
+			changed++
+			fmt.Fprintf(w, "~ %s\n", diffName(info))
+			for _, line := range hunks {
+				fmt.Fprintln(w, "  "+line)
+			}
+		}
+	}
+
+	if added+removed+changed == 0 {
+		fmt.Fprintln(w, tr(lang, "没有差异"))
+	} else {
+		fmt.Fprintln(w, tr(lang, "%d 个新增，%d 个删除，%d 个变更", added, removed, changed))
+	}
+	return added + removed + changed
+}
+
+// diff 子命令：以快照文件为旧版本、kubectl 的当前结果为新版本输出差异，存在差异时以退出码 1 结束
+func runDiff(cfg *cliConfig, resources []K8sResource, command string) {
+	snapshot, err := loadManifestFiles([]string{cfg.diffFrom})
+	if err != nil {
+		log.Fatalf("❌ Failed to read snapshot: %v", err)
+	}
+	before := generateResourceInfo(snapshot, false, defaultLang)
+	after := generateResourceInfo(resources, false, defaultLang)
+	if printResourceDiff(os.Stdout, cfg.diffFrom, "kubectl "+command, before, after, defaultLang) > 0 {
+		os.Exit(1)
+	}
+}
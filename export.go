@@ -0,0 +1,270 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 导出时额外去除的元数据字段（在精简视图基础上）
+var exportStripMetadataFields = []string{
+	"creationTimestamp",
+	"deletionTimestamp",
+	"deletionGracePeriodSeconds",
+	"generation",
+}
+
+// 深拷贝 YAML/JSON 解析出的通用结构
+func deepCopyValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = deepCopyValue(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = deepCopyValue(item)
+		}
+		return out
+	default:
+		return val
+	}
+}
+
+// 生成可重新 apply 的清单：去除 status、uid、resourceVersion、managedFields 等
+func exportReadyObject(obj map[string]interface{}) map[string]interface{} {
+	out := deepCopyValue(obj).(map[string]interface{})
+	delete(out, "status")
+
+	metadata, ok := out["metadata"].(map[string]interface{})
+	if !ok {
+		return out
+	}
+	for _, field := range noisyMetadataFields {
+		delete(metadata, field)
+	}
+	for _, field := range exportStripMetadataFields {
+		delete(metadata, field)
+	}
+	if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+		for _, key := range noisyAnnotations {
+			delete(annotations, key)
+		}
+		if len(annotations) == 0 {
+			delete(metadata, "annotations")
+		}
+	}
+	return out
+}
+
+// 根据请求参数选出要导出的资源：index 可重复或逗号分隔，未指定时导出全部
+func selectExportResources(infos []ResourceInfo, r *http.Request) ([]ResourceInfo, error) {
+	var raw []string
+	for _, v := range r.URL.Query()["index"] {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				raw = append(raw, part)
+			}
+		}
+	}
+	if len(raw) == 0 {
+		return infos, nil
+	}
+
+	selected := make([]ResourceInfo, 0, len(raw))
+	for _, part := range raw {
+		index, err := strconv.Atoi(part)
+		if err != nil || index < 0 || index >= len(infos) {
+			return nil, errorf("无效的资源索引: %s", part)
+		}
+		selected = append(selected, infos[index])
+	}
+	return selected, nil
+}
+
+// 导出对象列表
+func exportObjects(infos []ResourceInfo, exportReady bool) []map[string]interface{} {
+	objects := make([]map[string]interface{}, 0, len(infos))
+	for _, info := range infos {
+		if exportReady {
+			objects = append(objects, exportReadyObject(info.Parsed))
+		} else {
+			objects = append(objects, info.Parsed)
+		}
+	}
+	return objects
+}
+
+// 多文档 YAML
+func encodeMultiDocYAML(objects []map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, obj := range objects {
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		out, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(out)
+	}
+	return buf.Bytes(), nil
+}
+
+// kind: List 格式的 JSON
+func encodeListJSON(objects []map[string]interface{}) ([]byte, error) {
+	items := make([]interface{}, len(objects))
+	for i, obj := range objects {
+		items[i] = obj
+	}
+	list := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"metadata":   map[string]interface{}{},
+		"items":      items,
+	}
+	return json.MarshalIndent(list, "", "  ")
+}
+
+// 每个资源一个 YAML 文件的 zip 包，按命名空间分目录
+func encodeZip(infos []ResourceInfo, objects []map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	used := make(map[string]int)
+
+	for i, obj := range objects {
+		dir := infos[i].Namespace
+		if dir == "" {
+			dir = "_cluster"
+		}
+		name := fmt.Sprintf("%s/%s-%s", dir, strings.ToLower(infos[i].Kind), infos[i].Name)
+		used[name]++
+		if used[name] > 1 {
+			name = fmt.Sprintf("%s-%d", name, used[name])
+		}
+
+		out, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		f, err := zw.Create(sanitizeFileName(name) + ".yaml")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Write(out); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// 去除文件名中的不安全字符（保留目录分隔符）
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '\\', ':', '*', '?', '"', '<', '>', '|', ' ':
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// 导出文件名：单个资源使用 kind-name，否则使用通用名称
+func exportFileName(infos []ResourceInfo, ext string) string {
+	if len(infos) == 1 {
+		return sanitizeFileName(fmt.Sprintf("%s-%s.%s", strings.ToLower(infos[0].Kind), infos[0].Name, ext))
+	}
+	return "kubectl-html-export." + ext
+}
+
+// 支持的导出格式，同时作为文件扩展名
+var exportFormats = []string{"yaml", "json", "zip"}
+
+func validExportFormat(format string) bool {
+	for _, f := range exportFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// 按格式编码导出内容，返回内容和 Content-Type
+func encodeExport(infos []ResourceInfo, objects []map[string]interface{}, format string) ([]byte, string, error) {
+	switch format {
+	case "json":
+		body, err := encodeListJSON(objects)
+		return body, "application/json", err
+	case "zip":
+		body, err := encodeZip(infos, objects)
+		return body, "application/zip", err
+	default:
+		body, err := encodeMultiDocYAML(objects)
+		return body, "application/yaml", err
+	}
+}
+
+// 导出端点: /api/export?format=yaml|json|zip&index=1,2&clean=1
+func exportHandler(infos []ResourceInfo) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		selected, err := selectExportResources(infos, r)
+		if err != nil {
+			http.Error(w, localizeError(requestLanguage(r), err), http.StatusBadRequest)
+			return
+		}
+
+		exportReady := r.URL.Query().Get("clean") == "1" || r.URL.Query().Get("clean") == "true"
+		objects := exportObjects(selected, exportReady)
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = "yaml"
+		}
+		if !validExportFormat(format) {
+			http.Error(w, tr(requestLanguage(r), "不支持的导出格式: %s", format), http.StatusBadRequest)
+			return
+		}
+		body, contentType, err := encodeExport(selected, objects, format)
+		if err != nil {
+			http.Error(w, tr(requestLanguage(r), "导出失败: %v", err), http.StatusInternalServerError)
+			log.Printf("❌ Export error: %v", err)
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", exportFileName(selected, format)))
+		w.Write(body)
+	}
+}
+
+// export 子命令：导出 kubectl 返回的全部资源，默认去除服务端字段以便重新 apply
+func runExport(cfg *cliConfig, resources []K8sResource) {
+	infos := generateResourceInfo(resources, false, defaultLang)
+	body, _, err := encodeExport(infos, exportObjects(infos, cfg.cleanView), cfg.exportFormat)
+	if err != nil {
+		log.Fatalf("❌ Export error: %v", err)
+	}
+	if cfg.outputFile == "" {
+		os.Stdout.Write(body)
+		return
+	}
+	if err := os.WriteFile(cfg.outputFile, body, 0o644); err != nil {
+		log.Fatalf("❌ Export error: %v", err)
+	}
+	log.Printf("💾 Exported %d resources to %s", len(infos), cfg.outputFile)
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// GitOps 对象管理的一个资源；Index 为 -1 表示未加载
+type GitOpsManaged struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Index     int    `json:"index"`
+	Sync      string `json:"sync,omitempty"`
+	Health    string `json:"health,omitempty"`
+	Tracked   string `json:"tracked"` // 来源：status 中的清单或跟踪标签 / 注解
+}
+
+// Argo CD Application 或 Flux Kustomization / HelmRelease 的摘要
+type GitOpsApp struct {
+	Index          int             `json:"-"`
+	Tool           string          `json:"tool"`
+	Kind           string          `json:"kind"`
+	Namespace      string          `json:"namespace"`
+	Name           string          `json:"name"`
+	Sync           string          `json:"sync,omitempty"`
+	Health         string          `json:"health,omitempty"`
+	Message        string          `json:"message,omitempty"`
+	Source         string          `json:"source,omitempty"`
+	Path           string          `json:"path,omitempty"`
+	TargetRevision string          `json:"targetRevision,omitempty"`
+	Revision       string          `json:"revision,omitempty"`
+	Destination    string          `json:"destination,omitempty"`
+	LastSync       string          `json:"lastSync,omitempty"`
+	Suspended      bool            `json:"suspended,omitempty"`
+	Managed        []GitOpsManaged `json:"managed,omitempty"`
+	MissingManaged int             `json:"missingManaged"`
+	OutOfSyncCount int             `json:"outOfSyncCount"`
+}
+
+// 页面徽章使用的状态
+func (a GitOpsApp) StatusClass() string {
+	switch {
+	case a.Suspended:
+		return "status-pending"
+	case a.Health == "Degraded" || a.Health == "Missing":
+		return "status-failed"
+	case a.Sync == "OutOfSync" || a.Health == "Progressing" || a.Health == "Unknown":
+		return "status-pending"
+	case a.Health == "Healthy":
+		return "status-running"
+	}
+	return "status-unknown"
+}
+
+func isArgoApplication(apiVersion, kind string) bool {
+	return kind == "Application" && strings.HasPrefix(apiVersion, "argoproj.io/")
+}
+
+func isFluxKustomization(apiVersion, kind string) bool {
+	return kind == "Kustomization" && strings.HasPrefix(apiVersion, "kustomize.toolkit.fluxcd.io/")
+}
+
+func isFluxHelmRelease(apiVersion, kind string) bool {
+	return kind == "HelmRelease" && strings.HasPrefix(apiVersion, "helm.toolkit.fluxcd.io/")
+}
+
+// 按类型、命名空间、名称查找资源；apiVersion 分组不同但名称相同的资源很少见，不做区分
+type gitOpsIndex map[string]int
+
+func (idx gitOpsIndex) find(kind, namespace, name string) int {
+	if i, ok := idx[resourceKey(kind, namespace, name)]; ok {
+		return i
+	}
+	// 集群级资源
+	if i, ok := idx[resourceKey(kind, "", name)]; ok {
+		return i
+	}
+	return -1
+}
+
+// Flux Ready 条件的状态与消息
+func readyCondition(obj map[string]interface{}) (status, message string) {
+	for _, item := range nestedSlice(obj, "status", "conditions") {
+		c, ok := item.(map[string]interface{})
+		if ok && nestedString(c, "type") == "Ready" {
+			return nestedString(c, "status"), nestedString(c, "message")
+		}
+	}
+	return "", ""
+}
+
+func argoApplication(info ResourceInfo, idx gitOpsIndex) GitOpsApp {
+	obj := info.Parsed
+	app := GitOpsApp{
+		Tool:        "Argo CD",
+		Sync:        nestedString(obj, "status", "sync", "status"),
+		Health:      nestedString(obj, "status", "health", "status"),
+		Message:     nestedString(obj, "status", "health", "message"),
+		Revision:    nestedString(obj, "status", "sync", "revision"),
+		LastSync:    nestedString(obj, "status", "operationState", "finishedAt"),
+		Destination: nestedString(obj, "spec", "destination", "server"),
+	}
+	if app.Destination == "" {
+		app.Destination = nestedString(obj, "spec", "destination", "name")
+	}
+	if ns := nestedString(obj, "spec", "destination", "namespace"); ns != "" {
+		app.Destination += " / " + ns
+	}
+	source := nestedMap(obj, "spec", "source")
+	if source == nil {
+		// 多来源应用只显示第一个来源
+		if sources := nestedSlice(obj, "spec", "sources"); len(sources) > 0 {
+			source, _ = sources[0].(map[string]interface{})
+		}
+	}
+	app.Source = nestedString(source, "repoURL")
+	app.Path = nestedString(source, "path")
+	if chart := nestedString(source, "chart"); chart != "" {
+		app.Path = "chart " + chart
+	}
+	app.TargetRevision = nestedString(source, "targetRevision")
+	if app.Message == "" {
+		app.Message = nestedString(obj, "status", "operationState", "message")
+	}
+
+	for _, item := range nestedSlice(obj, "status", "resources") {
+		r, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		m := GitOpsManaged{
+			Kind:      nestedString(r, "kind"),
+			Namespace: nestedString(r, "namespace"),
+			Name:      nestedString(r, "name"),
+			Sync:      nestedString(r, "status"),
+			Health:    nestedString(r, "health", "status"),
+			Tracked:   "status.resources",
+		}
+		m.Index = idx.find(m.Kind, m.Namespace, m.Name)
+		app.Managed = append(app.Managed, m)
+	}
+	return app
+}
+
+func fluxSource(ref map[string]interface{}, namespace string) string {
+	if ref == nil {
+		return ""
+	}
+	ns := nestedString(ref, "namespace")
+	if ns == "" {
+		ns = namespace
+	}
+	return nestedString(ref, "kind") + " " + ns + "/" + nestedString(ref, "name")
+}
+
+var fluxHealth = map[string]string{
+	"True":    "Healthy",
+	"False":   "Degraded",
+	"Unknown": "Progressing",
+}
+
+func fluxApplication(info ResourceInfo, idx gitOpsIndex) GitOpsApp {
+	obj := info.Parsed
+	app := GitOpsApp{
+		Tool:        "Flux",
+		Revision:    nestedString(obj, "status", "lastAppliedRevision"),
+		Suspended:   nestedValue(obj, "spec", "suspend") == true,
+		Destination: nestedString(obj, "spec", "targetNamespace"),
+	}
+	// 将 Ready 条件映射为与 Argo CD 一致的健康状态
+	ready, message := readyCondition(obj)
+	app.Health, app.Message = fluxHealth[ready], message
+	if attempted := nestedString(obj, "status", "lastAttemptedRevision"); attempted != "" && attempted != app.Revision {
+		app.Sync = "OutOfSync"
+	} else if app.Revision != "" {
+		app.Sync = "Synced"
+	}
+	for _, item := range nestedSlice(obj, "status", "conditions") {
+		if c, ok := item.(map[string]interface{}); ok && nestedString(c, "type") == "Ready" {
+			app.LastSync = nestedString(c, "lastTransitionTime")
+		}
+	}
+
+	if isFluxKustomization(info.APIVersion, info.Kind) {
+		app.Source = fluxSource(nestedMap(obj, "spec", "sourceRef"), info.Namespace)
+		app.Path = nestedString(obj, "spec", "path")
+		// inventory 条目 ID 格式为 <namespace>_<name>_<group>_<kind>
+		for _, item := range nestedSlice(obj, "status", "inventory", "entries") {
+			entry, _ := item.(map[string]interface{})
+			parts := strings.Split(nestedString(entry, "id"), "_")
+			if len(parts) != 4 {
+				continue
+			}
+			m := GitOpsManaged{Kind: parts[3], Namespace: parts[0], Name: parts[1], Tracked: "status.inventory"}
+			m.Index = idx.find(m.Kind, m.Namespace, m.Name)
+			app.Managed = append(app.Managed, m)
+		}
+	} else {
+		chart := nestedMap(obj, "spec", "chart", "spec")
+		app.Source = fluxSource(nestedMap(chart, "sourceRef"), info.Namespace)
+		if app.Source == "" {
+			app.Source = fluxSource(nestedMap(obj, "spec", "chartRef"), info.Namespace)
+		}
+		if name := nestedString(chart, "chart"); name != "" {
+			app.Path = "chart " + name
+		}
+		app.TargetRevision = nestedString(chart, "version")
+		if app.Revision == "" {
+			// helm-controller v2 将已安装版本记录在 status.history 中
+			if history := nestedSlice(obj, "status", "history"); len(history) > 0 {
+				if h, ok := history[0].(map[string]interface{}); ok {
+					app.Revision = nestedString(h, "chartName") + "@" + nestedString(h, "chartVersion")
+				}
+			}
+		}
+		if app.Destination == "" {
+			app.Destination = nestedString(obj, "spec", "storageNamespace")
+		}
+	}
+	return app
+}
+
+// 资源上的跟踪标签 / 注解指向的 GitOps 对象（类型/命名空间/名称）
+func gitOpsOwners(info ResourceInfo) []string {
+	var owners []string
+	labels := resourceLabels(info)
+	if name := labels["kustomize.toolkit.fluxcd.io/name"]; name != "" {
+		owners = append(owners, resourceKey("Kustomization", labels["kustomize.toolkit.fluxcd.io/namespace"], name))
+	}
+	if name := labels["helm.toolkit.fluxcd.io/name"]; name != "" {
+		owners = append(owners, resourceKey("HelmRelease", labels["helm.toolkit.fluxcd.io/namespace"], name))
+	}
+	// Argo CD 默认使用 app.kubernetes.io/instance 标签跟踪，值为应用名
+	if name := labels["app.kubernetes.io/instance"]; name != "" {
+		owners = append(owners, resourceKey("Application", "", name))
+	}
+	// Argo CD 注解跟踪格式为 <app>:<group>/<kind>:<namespace>/<name>，应用名可带 <namespace>_ 前缀
+	if id := resourceAnnotations(info)["argocd.argoproj.io/tracking-id"]; id != "" {
+		app := strings.SplitN(id, ":", 2)[0]
+		namespace := ""
+		if parts := strings.SplitN(app, "_", 2); len(parts) == 2 {
+			namespace, app = parts[0], parts[1]
+		}
+		owners = append(owners, resourceKey("Application", namespace, app))
+	}
+	return owners
+}
+
+type GitOpsReport struct {
+	Apps []GitOpsApp
+}
+
+// 识别 Argo CD 和 Flux 对象，解析状态并将其管理的资源与已加载的资源匹配
+func buildGitOpsReport(infos []ResourceInfo, lang string) GitOpsReport {
+	var report GitOpsReport
+	idx := make(gitOpsIndex, len(infos))
+	for i, info := range infos {
+		idx[resourceKey(info.Kind, info.Namespace, info.Name)] = i
+	}
+
+	apps := make(map[string]*GitOpsApp)
+	var order []int
+	for i, info := range infos {
+		var app GitOpsApp
+		switch {
+		case isArgoApplication(info.APIVersion, info.Kind):
+			app = argoApplication(info, idx)
+		case isFluxKustomization(info.APIVersion, info.Kind), isFluxHelmRelease(info.APIVersion, info.Kind):
+			app = fluxApplication(info, idx)
+		default:
+			continue
+		}
+		app.Index, app.Kind, app.Namespace, app.Name = i, info.Kind, info.Namespace, info.Name
+		infos[i].GitOps = &app
+		order = append(order, i)
+		apps[resourceKey(info.Kind, info.Namespace, info.Name)] = infos[i].GitOps
+		// Argo CD 注解中的应用名不一定带命名空间
+		if app.Tool == "Argo CD" {
+			if _, exists := apps[resourceKey(info.Kind, "", info.Name)]; !exists {
+				apps[resourceKey(info.Kind, "", info.Name)] = infos[i].GitOps
+			}
+		}
+	}
+	if len(order) == 0 {
+		return report
+	}
+
+	// 补充 status 中未列出、但通过跟踪标签或注解声明归属的已加载资源
+	for i, info := range infos {
+		for _, owner := range gitOpsOwners(info) {
+			app := apps[owner]
+			if app == nil {
+				continue
+			}
+			known := false
+			for _, m := range app.Managed {
+				if m.Index == i {
+					known = true
+					break
+				}
+			}
+			if !known {
+				app.Managed = append(app.Managed, GitOpsManaged{
+					Kind: info.Kind, Namespace: info.Namespace, Name: info.Name, Index: i, Tracked: tr(lang, "标签 / 注解"),
+				})
+			}
+		}
+	}
+
+	for _, i := range order {
+		app := infos[i].GitOps
+		sort.SliceStable(app.Managed, func(a, b int) bool {
+			return app.Managed[a].Kind < app.Managed[b].Kind
+		})
+		for _, m := range app.Managed {
+			if m.Index < 0 {
+				app.MissingManaged++
+			}
+			if m.Sync == "OutOfSync" {
+				app.OutOfSyncCount++
+			}
+		}
+		report.Apps = append(report.Apps, *app)
+	}
+	return report
+}
+
+// Argo CD 和 Flux 对象的卡片状态，非 GitOps 对象返回 false
+func gitOpsStatus(apiVersion, kind string, status map[string]interface{}, spec interface{}) (string, bool) {
+	switch {
+	case isArgoApplication(apiVersion, kind):
+		health := nestedString(status, "health", "status")
+		switch {
+		case health == "Degraded" || health == "Missing":
+			return "failed", true
+		case health == "Progressing" || health == "Suspended" || nestedString(status, "sync", "status") == "OutOfSync":
+			return "pending", true
+		case health == "Healthy":
+			return "running", true
+		}
+		return "unknown", true
+	case isFluxKustomization(apiVersion, kind), isFluxHelmRelease(apiVersion, kind):
+		if specMap, ok := spec.(map[string]interface{}); ok && specMap["suspend"] == true {
+			return "pending", true
+		}
+		ready, _ := readyCondition(map[string]interface{}{"status": status})
+		switch ready {
+		case "True":
+			return "running", true
+		case "False":
+			return "failed", true
+		case "Unknown":
+			return "pending", true
+		}
+		return "unknown", true
+	}
+	return "", false
+}
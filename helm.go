@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Helm 写在其管理对象上的注解
+const (
+	helmReleaseNameAnnotation      = "meta.helm.sh/release-name"
+	helmReleaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
+)
+
+// release 存储中保存的 JSON，只解析展示需要的字段
+type helmReleaseRecord struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   int    `json:"version"`
+	Info      struct {
+		Status        string `json:"status"`
+		FirstDeployed string `json:"first_deployed"`
+		LastDeployed  string `json:"last_deployed"`
+		Description   string `json:"description"`
+	} `json:"info"`
+	Chart struct {
+		Metadata struct {
+			Name       string `json:"name"`
+			Version    string `json:"version"`
+			AppVersion string `json:"appVersion"`
+		} `json:"metadata"`
+	} `json:"chart"`
+	Config map[string]interface{} `json:"config"`
+}
+
+// 解码 release 数据：base64 后 gzip 压缩的 JSON（未压缩的旧格式也兼容）
+func decodeHelmRelease(encoded string) (*helmReleaseRecord, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errorf("base64 解码失败: %v", err)
+	}
+	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, errorf("gzip 解压失败: %v", err)
+		}
+		defer reader.Close()
+		if data, err = io.ReadAll(reader); err != nil {
+			return nil, errorf("gzip 解压失败: %v", err)
+		}
+	}
+	var record helmReleaseRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, errorf("JSON 解析失败: %v", err)
+	}
+	return &record, nil
+}
+
+// Secret 驱动的 release 数据额外经过一次 Kubernetes 的 base64 编码；ConfigMap 驱动则没有
+func helmReleaseData(info ResourceInfo) (string, bool) {
+	encoded := nestedString(info.Parsed, "data", "release")
+	switch {
+	case info.Kind == "Secret" && nestedString(info.Parsed, "type") == "helm.sh/release.v1":
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return "", true
+		}
+		return string(decoded), true
+	case info.Kind == "ConfigMap" && resourceLabels(info)["owner"] == "helm" && encoded != "":
+		return encoded, true
+	}
+	return "", false
+}
+
+// release 的一个版本
+type HelmRevision struct {
+	Index       int // release 存储对象在资源列表中的位置
+	Revision    int
+	Status      string
+	Chart       string
+	AppVersion  string
+	Updated     string
+	Description string
+}
+
+// release 状态对应的徽章样式
+func (r HelmRevision) StatusClass() string { return helmStatusClass(r.Status) }
+func (r HelmRelease) StatusClass() string  { return helmStatusClass(r.Status) }
+
+func helmStatusClass(status string) string {
+	switch {
+	case status == "deployed":
+		return "status-running"
+	case status == "failed":
+		return "status-failed"
+	case strings.HasPrefix(status, "pending"), status == "uninstalling":
+		return "status-pending"
+	}
+	return "status-unknown"
+}
+
+type HelmRelease struct {
+	Name       string
+	Namespace  string
+	Chart      string // 最新版本的 chart 名称-版本
+	AppVersion string
+	Status     string
+	Revision   int
+	Updated    string
+	Values     string // 用户提供的 values（YAML）
+	History    []HelmRevision
+	Resources  []int // 注解指向该 release 的已加载资源
+}
+
+// 资源声明所属的 release 在已加载的 release 存储中不存在
+type HelmOrphan struct {
+	Index     int
+	Kind      string
+	Namespace string
+	Name      string
+	Release   string
+}
+
+type HelmReport struct {
+	Releases       []HelmRelease
+	Orphans        []HelmOrphan
+	Errors         []string
+	StorageLoaded  bool // 是否加载了 release Secret / ConfigMap，未加载时无法判断 release 是否存在
+	ManagedObjects int
+}
+
+func (r HelmReport) Present() bool {
+	return len(r.Releases) > 0 || r.ManagedObjects > 0
+}
+
+// 资源所属的 Helm release（命名空间/名称），没有注解时返回空
+func helmReleaseOf(info ResourceInfo) (namespace, name string) {
+	annotations := resourceAnnotations(info)
+	name = annotations[helmReleaseNameAnnotation]
+	if name == "" {
+		return "", ""
+	}
+	namespace = annotations[helmReleaseNamespaceAnnotation]
+	if namespace == "" {
+		namespace = info.Namespace
+	}
+	return namespace, name
+}
+
+// 按 release 分组资源，解码 release 存储中的历史版本
+func buildHelmReport(infos []ResourceInfo, lang string) HelmReport {
+	var report HelmReport
+	releases := make(map[string]*HelmRelease)
+	var keys []string
+	release := func(namespace, name string) *HelmRelease {
+		key := namespace + "/" + name
+		if r, ok := releases[key]; ok {
+			return r
+		}
+		r := &HelmRelease{Name: name, Namespace: namespace}
+		releases[key] = r
+		keys = append(keys, key)
+		return r
+	}
+
+	values := make(map[string]map[string]interface{})
+	// 加载了 release 存储的命名空间，只有这些命名空间中的 release 才能判断是否存在
+	storageNamespaces := make(map[string]bool)
+	for i, info := range infos {
+		encoded, ok := helmReleaseData(info)
+		if !ok {
+			continue
+		}
+		report.StorageLoaded = true
+		record, err := decodeHelmRelease(encoded)
+		if err != nil {
+			report.Errors = append(report.Errors, tr(lang, "%s %s/%s: %v", info.Kind, info.Namespace, info.Name, err))
+			continue
+		}
+		storageNamespaces[info.Namespace] = true
+		if record.Namespace == "" {
+			record.Namespace = info.Namespace
+		}
+		r := release(record.Namespace, record.Name)
+		r.History = append(r.History, HelmRevision{
+			Index:       i,
+			Revision:    record.Version,
+			Status:      record.Info.Status,
+			Chart:       record.Chart.Metadata.Name + "-" + record.Chart.Metadata.Version,
+			AppVersion:  record.Chart.Metadata.AppVersion,
+			Updated:     record.Info.LastDeployed,
+			Description: record.Info.Description,
+		})
+		if record.Version >= r.Revision {
+			r.Revision = record.Version
+			values[record.Namespace+"/"+record.Name] = record.Config
+		}
+	}
+
+	for i, info := range infos {
+		namespace, name := helmReleaseOf(info)
+		if name == "" {
+			continue
+		}
+		report.ManagedObjects++
+		key := namespace + "/" + name
+		r, ok := releases[key]
+		if ok && len(r.History) > 0 {
+			latest := r.History[0]
+			for _, h := range r.History {
+				if h.Revision > latest.Revision {
+					latest = h
+				}
+			}
+			if latest.Status != "uninstalled" && latest.Status != "uninstalling" {
+				r.Resources = append(r.Resources, i)
+				continue
+			}
+		}
+		if storageNamespaces[namespace] {
+			report.Orphans = append(report.Orphans, HelmOrphan{Index: i, Kind: info.Kind, Namespace: info.Namespace, Name: info.Name, Release: key})
+			continue
+		}
+		release(namespace, name).Resources = append(release(namespace, name).Resources, i)
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		r := releases[key]
+		sort.Slice(r.History, func(i, j int) bool { return r.History[i].Revision > r.History[j].Revision })
+		if len(r.History) > 0 {
+			latest := r.History[0]
+			r.Chart, r.AppVersion, r.Status, r.Updated = latest.Chart, latest.AppVersion, latest.Status, latest.Updated
+		}
+		// 未加载 release 存储时从 helm.sh/chart 标签获取 chart 信息
+		if r.Chart == "" && len(r.Resources) > 0 {
+			r.Chart = resourceLabels(infos[r.Resources[0]])["helm.sh/chart"]
+		}
+		if v := values[key]; len(v) > 0 {
+			if out, err := yaml.Marshal(v); err == nil {
+				r.Values = strings.TrimRight(string(out), "\n")
+			}
+		}
+		report.Releases = append(report.Releases, *r)
+	}
+	return report
+}
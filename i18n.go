@@ -0,0 +1,170 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// 界面语言。源字符串为中文，中文无需消息目录；其他语言的目录位于 locales/<lang>.json，
+// 以中文源字符串（含 %s、%d 等格式占位符）为键
+const (
+	langZH = "zh"
+	langEN = "en"
+)
+
+// 支持的语言及其在切换器中的名称
+var languages = []Language{
+	{Code: langZH, Name: "中文"},
+	{Code: langEN, Name: "English"},
+}
+
+type Language struct {
+	Code string
+	Name string
+}
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// 各语言的消息目录
+var catalogs = loadCatalogs()
+
+func loadCatalogs() map[string]map[string]string {
+	out := make(map[string]map[string]string)
+	for _, l := range languages {
+		data, err := localeFiles.ReadFile("locales/" + l.Code + ".json")
+		if err != nil {
+			continue
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			log.Printf("⚠️  Warning: Invalid message catalog %s: %v", l.Code, err)
+			continue
+		}
+		out[l.Code] = messages
+	}
+	return out
+}
+
+// 服务端默认语言，由 --lang 或环境变量决定
+var defaultLang = langZH
+
+func supportedLanguage(code string) bool {
+	for _, l := range languages {
+		if l.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// 将 en_US.UTF-8、zh-CN 等写法归一化为支持的语言，不支持时返回空
+func normalizeLanguage(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if i := strings.IndexAny(value, "_-.@"); i >= 0 {
+		value = value[:i]
+	}
+	if supportedLanguage(value) {
+		return value
+	}
+	return ""
+}
+
+// 按 --lang、LC_ALL、LC_MESSAGES、LANG 的顺序确定语言；都未指定或不支持时使用中文
+func detectLanguage(flagValue string) string {
+	if flagValue != "" {
+		return normalizeLanguage(flagValue)
+	}
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(env); v != "" {
+			if lang := normalizeLanguage(v); lang != "" {
+				return lang
+			}
+			// 设置了但不支持的语言（如 C、POSIX）不再继续向后查找，与 POSIX 的优先级一致
+			return langZH
+		}
+	}
+	return langZH
+}
+
+// 页面语言的 cookie，切换语言后在刷新和 API 请求中保持
+const localeCookie = "kubectl-html-locale"
+
+// 请求的界面语言：?locale= 参数优先，其次 cookie，最后为服务端默认语言
+func requestLanguage(r *http.Request) string {
+	if lang := normalizeLanguage(r.URL.Query().Get("locale")); lang != "" {
+		return lang
+	}
+	if c, err := r.Cookie(localeCookie); err == nil {
+		if lang := normalizeLanguage(c.Value); lang != "" {
+			return lang
+		}
+	}
+	return defaultLang
+}
+
+// 翻译消息，有参数时按格式化字符串处理；目录中没有的消息原样返回中文
+func tr(lang, msg string, args ...interface{}) string {
+	if translated, ok := catalogs[lang][msg]; ok && translated != "" {
+		msg = translated
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	// 嵌套的可翻译错误按同一语言输出
+	localized := make([]interface{}, len(args))
+	for i, arg := range args {
+		if err, ok := arg.(error); ok {
+			arg = localizeError(lang, err)
+		}
+		localized[i] = arg
+	}
+	return fmt.Sprintf(msg, localized...)
+}
+
+// 可翻译的错误，保存格式化字符串和参数，输出时再按请求语言格式化
+type localizedError struct {
+	format string
+	args   []interface{}
+}
+
+func (e *localizedError) Error() string {
+	return fmt.Sprintf(e.format, e.args...)
+}
+
+func errorf(format string, args ...interface{}) error {
+	return &localizedError{format: format, args: args}
+}
+
+func localizeError(lang string, err error) string {
+	var le *localizedError
+	if errors.As(err, &le) {
+		return tr(lang, le.format, le.args...)
+	}
+	return err.Error()
+}
+
+// 模板函数 T，与 tr 相同但绑定了请求语言
+func templateFuncs(lang string) template.FuncMap {
+	return template.FuncMap{
+		"T": func(msg string, args ...interface{}) string {
+			return tr(lang, msg, args...)
+		},
+	}
+}
+
+// 前端 t() 使用的消息目录
+func catalogJSON(lang string) template.JS {
+	data, err := json.Marshal(catalogs[lang])
+	if err != nil || catalogs[lang] == nil {
+		return template.JS("{}")
+	}
+	return template.JS(data)
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 未指定镜像仓库时的默认仓库
+const defaultRegistry = "docker.io"
+
+// 解析后的镜像引用
+type ImageReference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// 按 docker 规则解析镜像引用，如 nginx、ghcr.io/org/app:v1、busybox@sha256:...
+func parseImageReference(image string) ImageReference {
+	var ref ImageReference
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		ref.Digest = name[i+1:]
+		name = name[:i]
+	}
+	// 标签只能出现在最后一个路径段中，避免把仓库端口当作标签
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		ref.Tag = name[i+1:]
+		name = name[:i]
+	}
+
+	ref.Registry = defaultRegistry
+	if i := strings.Index(name, "/"); i >= 0 {
+		first := name[:i]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			ref.Registry = first
+			name = name[i+1:]
+		}
+		// 旧的 Docker Hub 地址与 docker.io 是同一个仓库
+		if ref.Registry == "index.docker.io" {
+			ref.Registry = defaultRegistry
+		}
+	}
+	if ref.Registry == defaultRegistry && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	ref.Repository = name
+	return ref
+}
+
+// 镜像版本：有摘要时为摘要，否则为标签（未指定时为 latest）
+func (ref ImageReference) Version() string {
+	if ref.Digest != "" {
+		return "@" + ref.Digest
+	}
+	if ref.Tag == "" {
+		return "latest"
+	}
+	return ref.Tag
+}
+
+// 镜像被某个工作负载容器使用
+type ImageUsage struct {
+	Index     int    `json:"index"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Container string `json:"container"`
+	Init      bool   `json:"init"`
+}
+
+type ImageEntry struct {
+	Image      string       `json:"image"`
+	Registry   string       `json:"registry"`
+	Repository string       `json:"repository"`
+	Tag        string       `json:"tag"`
+	Digest     string       `json:"digest"`
+	Usages     []ImageUsage `json:"usages"`
+	Namespaces []string     `json:"namespaces"`
+	Latest     bool         `json:"latest"`              // 使用 latest 标签或未指定标签
+	NoDigest   bool         `json:"noDigest"`            // 未固定摘要
+	MixedTags  bool         `json:"mixedTags"`           // 同一镜像在不同命名空间中使用了不同版本
+	OtherTags  []string     `json:"otherTags,omitempty"` // 同一镜像的其他版本
+}
+
+type RegistryStat struct {
+	Registry string `json:"registry"`
+	Images   int    `json:"images"`
+	Usages   int    `json:"usages"`
+}
+
+type ImageInventory struct {
+	Images        []ImageEntry   `json:"images"`
+	Registries    []RegistryStat `json:"registries"`
+	Usages        int            `json:"usages"`
+	LatestCount   int            `json:"latestCount"`
+	NoDigestCount int            `json:"noDigestCount"`
+	MixedCount    int            `json:"mixedCount"`
+}
+
+// 汇总指定资源中工作负载容器和 init 容器使用的镜像
+func buildImageInventory(infos []ResourceInfo, indices []int) ImageInventory {
+	entries := make(map[string]*ImageEntry)
+	inventory := ImageInventory{Images: []ImageEntry{}, Registries: []RegistryStat{}}
+
+	for _, index := range indices {
+		info := infos[index]
+		for _, c := range podContainers(podSpec(info)) {
+			image := nestedString(c.Spec, "image")
+			if image == "" {
+				continue
+			}
+			entry, ok := entries[image]
+			if !ok {
+				ref := parseImageReference(image)
+				entry = &ImageEntry{
+					Image:      image,
+					Registry:   ref.Registry,
+					Repository: ref.Repository,
+					Tag:        ref.Tag,
+					Digest:     ref.Digest,
+					Latest:     ref.Digest == "" && (ref.Tag == "" || ref.Tag == "latest"),
+					NoDigest:   ref.Digest == "",
+				}
+				entries[image] = entry
+			}
+			entry.Usages = append(entry.Usages, ImageUsage{
+				Index:     index,
+				Kind:      info.Kind,
+				Namespace: info.Namespace,
+				Name:      info.Name,
+				Container: c.Name,
+				Init:      c.Init,
+			})
+			inventory.Usages++
+		}
+	}
+
+	// 按仓库分组，检查同一镜像在不同命名空间中的版本差异
+	type repoVersions struct {
+		versions   map[string]bool
+		namespaces map[string]bool
+	}
+	repos := make(map[string]*repoVersions)
+	registries := make(map[string]*RegistryStat)
+	for _, entry := range entries {
+		namespaces := make(map[string]bool)
+		for _, u := range entry.Usages {
+			namespaces[u.Namespace] = true
+		}
+		for ns := range namespaces {
+			entry.Namespaces = append(entry.Namespaces, ns)
+		}
+		sort.Strings(entry.Namespaces)
+
+		key := entry.Registry + "/" + entry.Repository
+		repo, ok := repos[key]
+		if !ok {
+			repo = &repoVersions{versions: make(map[string]bool), namespaces: make(map[string]bool)}
+			repos[key] = repo
+		}
+		repo.versions[ImageReference{Tag: entry.Tag, Digest: entry.Digest}.Version()] = true
+		for ns := range namespaces {
+			repo.namespaces[ns] = true
+		}
+
+		stat, ok := registries[entry.Registry]
+		if !ok {
+			stat = &RegistryStat{Registry: entry.Registry}
+			registries[entry.Registry] = stat
+		}
+		stat.Images++
+		stat.Usages += len(entry.Usages)
+	}
+
+	for _, entry := range entries {
+		repo := repos[entry.Registry+"/"+entry.Repository]
+		if len(repo.versions) > 1 && len(repo.namespaces) > 1 {
+			entry.MixedTags = true
+			own := ImageReference{Tag: entry.Tag, Digest: entry.Digest}.Version()
+			for v := range repo.versions {
+				if v != own {
+					entry.OtherTags = append(entry.OtherTags, v)
+				}
+			}
+			sort.Strings(entry.OtherTags)
+		}
+
+		if entry.Latest {
+			inventory.LatestCount++
+		}
+		if entry.NoDigest {
+			inventory.NoDigestCount++
+		}
+		if entry.MixedTags {
+			inventory.MixedCount++
+		}
+		inventory.Images = append(inventory.Images, *entry)
+	}
+
+	sort.Slice(inventory.Images, func(i, j int) bool {
+		a, b := inventory.Images[i], inventory.Images[j]
+		if a.Registry+"/"+a.Repository != b.Registry+"/"+b.Repository {
+			return a.Registry+"/"+a.Repository < b.Registry+"/"+b.Repository
+		}
+		return a.Image < b.Image
+	})
+	for _, stat := range registries {
+		inventory.Registries = append(inventory.Registries, *stat)
+	}
+	sort.Slice(inventory.Registries, func(i, j int) bool {
+		if inventory.Registries[i].Usages != inventory.Registries[j].Usages {
+			return inventory.Registries[i].Usages > inventory.Registries[j].Usages
+		}
+		return inventory.Registries[i].Registry < inventory.Registries[j].Registry
+	})
+	return inventory
+}
+
+// 所有资源的索引
+func allIndices(infos []ResourceInfo) []int {
+	indices := make([]int, len(infos))
+	for i := range indices {
+		indices[i] = i
+	}
+	return indices
+}
+
+// 每个镜像使用一行的 CSV
+func encodeImageCSV(inventory ImageInventory) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"image", "registry", "repository", "tag", "digest", "kind", "namespace", "name", "container", "init", "latest", "no_digest", "mixed_tags"})
+	for _, entry := range inventory.Images {
+		for _, u := range entry.Usages {
+			w.Write([]string{
+				entry.Image, entry.Registry, entry.Repository, entry.Tag, entry.Digest,
+				u.Kind, u.Namespace, u.Name, u.Container,
+				strconv.FormatBool(u.Init),
+				strconv.FormatBool(entry.Latest),
+				strconv.FormatBool(entry.NoDigest),
+				strconv.FormatBool(entry.MixedTags),
+			})
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// GET /api/v1/images?format=json|csv，可与 /api/v1/resources 的过滤参数组合使用
+func apiImagesHandler(pageFor func(*http.Request) PageData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		infos := pageFor(r).Resources
+		indices, err := filterResources(infos, r)
+		if err != nil {
+			writeAPIError(w, r, http.StatusBadRequest, "%v", err)
+			return
+		}
+		inventory := buildImageInventory(infos, indices)
+
+		switch format := r.URL.Query().Get("format"); format {
+		case "", "json":
+			writeJSON(w, http.StatusOK, inventory)
+		case "csv":
+			body, err := encodeImageCSV(inventory)
+			if err != nil {
+				writeAPIError(w, r, http.StatusInternalServerError, "生成 CSV 失败: %v", err)
+				return
+			}
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			w.Header().Set("Content-Disposition", `attachment; filename="kubectl-html-images.csv"`)
+			w.Write(body)
+		default:
+			writeAPIError(w, r, http.StatusBadRequest, "不支持的格式: %s（可选 json、csv）", format)
+		}
+	}
+}
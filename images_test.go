@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+func TestParseImageReference(t *testing.T) {
+	const digest = "sha256:4c5f3dd7f5a4c0e1c3ed7b1c2a9d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d"
+	tests := []struct {
+		image   string
+		want    ImageReference
+		version string
+	}{
+		{"nginx", ImageReference{"docker.io", "library/nginx", "", ""}, "latest"},
+		{"nginx:1.25", ImageReference{"docker.io", "library/nginx", "1.25", ""}, "1.25"},
+		{"bitnami/redis:7.2", ImageReference{"docker.io", "bitnami/redis", "7.2", ""}, "7.2"},
+		{"docker.io/nginx", ImageReference{"docker.io", "library/nginx", "", ""}, "latest"},
+		{"docker.io/library/nginx:latest", ImageReference{"docker.io", "library/nginx", "latest", ""}, "latest"},
+		{"index.docker.io/nginx:1.25", ImageReference{"docker.io", "library/nginx", "1.25", ""}, "1.25"},
+		{"ghcr.io/org/app:v1", ImageReference{"ghcr.io", "org/app", "v1", ""}, "v1"},
+		{"registry.k8s.io/kube-apiserver:v1.29.0", ImageReference{"registry.k8s.io", "kube-apiserver", "v1.29.0", ""}, "v1.29.0"},
+		{"host:5000/img", ImageReference{"host:5000", "img", "", ""}, "latest"},
+		{"host:5000/team/img:2.0", ImageReference{"host:5000", "team/img", "2.0", ""}, "2.0"},
+		{"localhost/img:dev", ImageReference{"localhost", "img", "dev", ""}, "dev"},
+		{"localhost:5000/img", ImageReference{"localhost:5000", "img", "", ""}, "latest"},
+		{"10.0.0.1:5000/img:1", ImageReference{"10.0.0.1:5000", "img", "1", ""}, "1"},
+		{"busybox@" + digest, ImageReference{"docker.io", "library/busybox", "", digest}, "@" + digest},
+		{"busybox:1.36@" + digest, ImageReference{"docker.io", "library/busybox", "1.36", digest}, "@" + digest},
+		{"host:5000/img@" + digest, ImageReference{"host:5000", "img", "", digest}, "@" + digest},
+		{"host:5000/img:3@" + digest, ImageReference{"host:5000", "img", "3", digest}, "@" + digest},
+	}
+	for _, tt := range tests {
+		got := parseImageReference(tt.image)
+		if got != tt.want {
+			t.Errorf("parseImageReference(%q) = %+v, 期望 %+v", tt.image, got, tt.want)
+		}
+		if v := got.Version(); v != tt.version {
+			t.Errorf("parseImageReference(%q).Version() = %q, 期望 %q", tt.image, v, tt.version)
+		}
+	}
+}
@@ -0,0 +1,1208 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// jq 子集：路径访问、迭代、管道、逗号、select/map 等常用函数、比较与逻辑运算、
+// 数组和对象构造。对 null 的字段访问和迭代不会报错，便于跨资源类型查询。
+
+type jqNode interface {
+	eval(input interface{}) ([]interface{}, error)
+}
+
+// ========== 词法分析 ==========
+
+type jqTokenKind int
+
+const (
+	jqEOF jqTokenKind = iota
+	jqIdent
+	jqField
+	jqString
+	jqNumber
+	jqPunct
+)
+
+type jqToken struct {
+	kind  jqTokenKind
+	text  string
+	value interface{}
+}
+
+func lexJQ(input string) ([]jqToken, error) {
+	var tokens []jqToken
+	i := 0
+	for i < len(input) {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(input) && input[end] != '"' {
+				if input[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(input) {
+				return nil, errorf("jq 字符串未闭合")
+			}
+			s, err := strconv.Unquote(input[i : end+1])
+			if err != nil {
+				return nil, errorf("jq 字符串无效: %s", input[i:end+1])
+			}
+			tokens = append(tokens, jqToken{kind: jqString, text: input[i : end+1], value: s})
+			i = end + 1
+		case c == '.':
+			// .foo 形式的字段访问
+			end := i + 1
+			for end < len(input) && isJQIdentChar(input[end]) {
+				end++
+			}
+			if end > i+1 {
+				tokens = append(tokens, jqToken{kind: jqField, text: input[i+1 : end]})
+			} else {
+				tokens = append(tokens, jqToken{kind: jqPunct, text: "."})
+			}
+			i = end
+		case c >= '0' && c <= '9':
+			end := i
+			for end < len(input) && (input[end] >= '0' && input[end] <= '9' || input[end] == '.') {
+				end++
+			}
+			n, err := strconv.ParseFloat(input[i:end], 64)
+			if err != nil {
+				return nil, errorf("jq 数字无效: %s", input[i:end])
+			}
+			tokens = append(tokens, jqToken{kind: jqNumber, text: input[i:end], value: n})
+			i = end
+		case isJQIdentChar(c) || c == '$':
+			end := i + 1
+			for end < len(input) && isJQIdentChar(input[end]) {
+				end++
+			}
+			tokens = append(tokens, jqToken{kind: jqIdent, text: input[i:end]})
+			i = end
+		default:
+			matched := false
+			for _, p := range []string{"==", "!=", "<=", ">=", "//", "|", ",", "(", ")", "[", "]", "{", "}", ":", "?", "<", ">", "-"} {
+				if strings.HasPrefix(input[i:], p) {
+					tokens = append(tokens, jqToken{kind: jqPunct, text: p})
+					i += len(p)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, errorf("jq 表达式中无法识别的字符 %q", c)
+			}
+		}
+	}
+	return append(tokens, jqToken{kind: jqEOF}), nil
+}
+
+func isJQIdentChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// ========== 语法分析 ==========
+
+type jqParser struct {
+	tokens []jqToken
+	pos    int
+}
+
+func compileJQ(input string) (jqNode, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, errorf("jq 表达式为空")
+	}
+	tokens, err := lexJQ(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &jqParser{tokens: tokens}
+	node, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != jqEOF {
+		return nil, errorf("jq 表达式多余的内容: %s", p.peek().text)
+	}
+	return node, nil
+}
+
+func (p *jqParser) peek() jqToken {
+	return p.tokens[p.pos]
+}
+
+func (p *jqParser) next() jqToken {
+	t := p.tokens[p.pos]
+	if t.kind != jqEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *jqParser) isPunct(text string) bool {
+	t := p.peek()
+	return t.kind == jqPunct && t.text == text
+}
+
+func (p *jqParser) isIdent(text string) bool {
+	t := p.peek()
+	return t.kind == jqIdent && t.text == text
+}
+
+func (p *jqParser) expect(text string) error {
+	if !p.isPunct(text) {
+		return errorf("jq 表达式缺少 %s", text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *jqParser) parsePipe() (jqNode, error) {
+	left, err := p.parseComma()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("|") {
+		p.next()
+		right, err := p.parseComma()
+		if err != nil {
+			return nil, err
+		}
+		left = &jqPipe{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *jqParser) parseComma() (jqNode, error) {
+	left, err := p.parseAlternative()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct(",") {
+		p.next()
+		right, err := p.parseAlternative()
+		if err != nil {
+			return nil, err
+		}
+		left = &jqComma{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *jqParser) parseAlternative() (jqNode, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("//") {
+		p.next()
+		right, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		left = &jqAlternative{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *jqParser) parseOr() (jqNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isIdent("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &jqLogic{op: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *jqParser) parseAnd() (jqNode, error) {
+	left, err := p.parseCompare()
+	if err != nil {
+		return nil, err
+	}
+	for p.isIdent("and") {
+		p.next()
+		right, err := p.parseCompare()
+		if err != nil {
+			return nil, err
+		}
+		left = &jqLogic{op: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *jqParser) parseCompare() (jqNode, error) {
+	left, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.isPunct(op) {
+			p.next()
+			right, err := p.parsePostfix()
+			if err != nil {
+				return nil, err
+			}
+			return &jqCompare{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *jqParser) parsePostfix() (jqNode, error) {
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == jqField:
+			p.next()
+			node = &jqIndex{target: node, key: &jqLiteral{value: t.text}}
+		case t.kind == jqPunct && t.text == "." && p.tokens[p.pos+1].kind == jqString:
+			p.next()
+			key := p.next()
+			node = &jqIndex{target: node, key: &jqLiteral{value: key.value}}
+		case t.kind == jqPunct && t.text == "." && p.tokens[p.pos+1].kind == jqPunct && p.tokens[p.pos+1].text == "[":
+			p.next()
+		case t.kind == jqPunct && t.text == "[":
+			p.next()
+			node, err = p.parseBracketSuffix(node)
+			if err != nil {
+				return nil, err
+			}
+		case t.kind == jqPunct && t.text == "?":
+			p.next()
+			node = &jqTry{body: node}
+		default:
+			return node, nil
+		}
+	}
+}
+
+// 解析 [] / [expr] / [from:to]，调用时 [ 已被消费
+func (p *jqParser) parseBracketSuffix(target jqNode) (jqNode, error) {
+	if p.isPunct("]") {
+		p.next()
+		return &jqIterate{target: target}, nil
+	}
+
+	var from, to jqNode
+	var err error
+	if !p.isPunct(":") {
+		from, err = p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if p.isPunct(":") {
+		p.next()
+		if !p.isPunct("]") {
+			to, err = p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return &jqSlice{target: target, from: from, to: to}, nil
+	}
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	return &jqIndex{target: target, key: from}, nil
+}
+
+func (p *jqParser) parsePrimary() (jqNode, error) {
+	t := p.next()
+	switch t.kind {
+	case jqField:
+		return &jqIndex{target: jqIdentity{}, key: &jqLiteral{value: t.text}}, nil
+	case jqString:
+		return &jqLiteral{value: t.value}, nil
+	case jqNumber:
+		return &jqLiteral{value: t.value}, nil
+	case jqIdent:
+		switch t.text {
+		case "true":
+			return &jqLiteral{value: true}, nil
+		case "false":
+			return &jqLiteral{value: false}, nil
+		case "null":
+			return &jqLiteral{value: nil}, nil
+		}
+		var args []jqNode
+		if p.isPunct("(") {
+			p.next()
+			arg, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			if !p.isPunct(")") {
+				return nil, errorf("函数 %s 的参数缺少 )", t.text)
+			}
+			p.next()
+			args = append(args, arg)
+		}
+		return newJQFunc(t.text, args)
+	case jqPunct:
+		switch t.text {
+		case ".":
+			if p.peek().kind == jqString {
+				key := p.next()
+				return &jqIndex{target: jqIdentity{}, key: &jqLiteral{value: key.value}}, nil
+			}
+			return jqIdentity{}, nil
+		case "(":
+			node, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return node, nil
+		case "[":
+			if p.isPunct("]") {
+				p.next()
+				return &jqArray{}, nil
+			}
+			body, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			return &jqArray{body: body}, nil
+		case "{":
+			return p.parseObject()
+		case "-":
+			if p.peek().kind == jqNumber {
+				n := p.next()
+				return &jqLiteral{value: -n.value.(float64)}, nil
+			}
+		}
+	}
+	if t.kind == jqEOF {
+		return nil, errorf("jq 表达式不完整")
+	}
+	return nil, errorf("jq 表达式中意外的 %s", t.text)
+}
+
+// 解析对象构造 {a: .b, "c": .d, e, (.k): .v}，调用时 { 已被消费
+func (p *jqParser) parseObject() (jqNode, error) {
+	obj := &jqObject{}
+	for !p.isPunct("}") {
+		var key jqNode
+		var shorthand string
+		t := p.next()
+		switch {
+		case t.kind == jqIdent:
+			key, shorthand = &jqLiteral{value: t.text}, t.text
+		case t.kind == jqString:
+			key, shorthand = &jqLiteral{value: t.value}, t.value.(string)
+		case t.kind == jqPunct && t.text == "(":
+			k, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			key = k
+		default:
+			return nil, errorf("jq 对象构造中无效的键: %s", t.text)
+		}
+
+		var value jqNode
+		if p.isPunct(":") {
+			p.next()
+			v, err := p.parseAlternative()
+			if err != nil {
+				return nil, err
+			}
+			value = v
+		} else if shorthand != "" {
+			value = &jqIndex{target: jqIdentity{}, key: &jqLiteral{value: shorthand}}
+		} else {
+			return nil, errorf("jq 对象构造缺少 :")
+		}
+		obj.keys = append(obj.keys, key)
+		obj.values = append(obj.values, value)
+
+		if p.isPunct(",") {
+			p.next()
+			continue
+		}
+		if !p.isPunct("}") {
+			return nil, errorf("jq 对象构造缺少 }")
+		}
+	}
+	p.next()
+	return obj, nil
+}
+
+// ========== 节点实现 ==========
+
+type jqIdentity struct{}
+
+func (jqIdentity) eval(input interface{}) ([]interface{}, error) {
+	return []interface{}{input}, nil
+}
+
+type jqLiteral struct{ value interface{} }
+
+func (n *jqLiteral) eval(interface{}) ([]interface{}, error) {
+	return []interface{}{n.value}, nil
+}
+
+type jqPipe struct{ left, right jqNode }
+
+func (n *jqPipe) eval(input interface{}) ([]interface{}, error) {
+	lefts, err := n.left.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, l := range lefts {
+		rights, err := n.right.eval(l)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rights...)
+	}
+	return out, nil
+}
+
+type jqComma struct{ left, right jqNode }
+
+func (n *jqComma) eval(input interface{}) ([]interface{}, error) {
+	lefts, err := n.left.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	rights, err := n.right.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	return append(lefts, rights...), nil
+}
+
+type jqAlternative struct{ left, right jqNode }
+
+func (n *jqAlternative) eval(input interface{}) ([]interface{}, error) {
+	lefts, _ := n.left.eval(input)
+	var out []interface{}
+	for _, l := range lefts {
+		if jqTruthy(l) {
+			out = append(out, l)
+		}
+	}
+	if len(out) > 0 {
+		return out, nil
+	}
+	return n.right.eval(input)
+}
+
+type jqLogic struct {
+	op          string
+	left, right jqNode
+}
+
+func (n *jqLogic) eval(input interface{}) ([]interface{}, error) {
+	lefts, err := n.left.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, l := range lefts {
+		if n.op == "and" && !jqTruthy(l) {
+			out = append(out, false)
+			continue
+		}
+		if n.op == "or" && jqTruthy(l) {
+			out = append(out, true)
+			continue
+		}
+		rights, err := n.right.eval(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rights {
+			out = append(out, jqTruthy(r))
+		}
+	}
+	return out, nil
+}
+
+type jqCompare struct {
+	op          string
+	left, right jqNode
+}
+
+func (n *jqCompare) eval(input interface{}) ([]interface{}, error) {
+	lefts, err := n.left.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	rights, err := n.right.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, r := range rights {
+		for _, l := range lefts {
+			out = append(out, compareValues(l, r, n.op))
+		}
+	}
+	return out, nil
+}
+
+type jqIndex struct {
+	target jqNode
+	key    jqNode
+}
+
+func (n *jqIndex) eval(input interface{}) ([]interface{}, error) {
+	targets, err := n.target.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := n.key.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, t := range targets {
+		for _, k := range keys {
+			v, err := jqIndexValue(t, k)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func jqIndexValue(target, key interface{}) (interface{}, error) {
+	if target == nil {
+		return nil, nil
+	}
+	switch k := key.(type) {
+	case string:
+		m, ok := target.(map[string]interface{})
+		if !ok {
+			return nil, errorf("无法用字符串 %q 索引 %s", k, jqTypeName(target))
+		}
+		return m[k], nil
+	default:
+		f, ok := toFloat(key)
+		if !ok {
+			return nil, errorf("无法用 %s 作为索引", jqTypeName(key))
+		}
+		list, ok := target.([]interface{})
+		if !ok {
+			return nil, errorf("无法用数字索引 %s", jqTypeName(target))
+		}
+		i := int(f)
+		if i < 0 {
+			i += len(list)
+		}
+		if i < 0 || i >= len(list) {
+			return nil, nil
+		}
+		return list[i], nil
+	}
+}
+
+type jqSlice struct {
+	target   jqNode
+	from, to jqNode
+}
+
+func (n *jqSlice) eval(input interface{}) ([]interface{}, error) {
+	targets, err := n.target.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	bound := func(node jqNode) (*int, error) {
+		if node == nil {
+			return nil, nil
+		}
+		vs, err := node.eval(input)
+		if err != nil || len(vs) == 0 {
+			return nil, err
+		}
+		f, ok := toFloat(vs[0])
+		if !ok {
+			return nil, errorf("切片边界必须是数字")
+		}
+		i := int(f)
+		return &i, nil
+	}
+	from, err := bound(n.from)
+	if err != nil {
+		return nil, err
+	}
+	to, err := bound(n.to)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []interface{}
+	for _, t := range targets {
+		switch v := t.(type) {
+		case nil:
+			out = append(out, nil)
+		case []interface{}:
+			out = append(out, applySlice(v, [3]*int{from, to, nil}))
+		case string:
+			runes := []rune(v)
+			items := make([]interface{}, len(runes))
+			for i, r := range runes {
+				items[i] = string(r)
+			}
+			var b strings.Builder
+			for _, r := range applySlice(items, [3]*int{from, to, nil}) {
+				b.WriteString(r.(string))
+			}
+			out = append(out, b.String())
+		default:
+			return nil, errorf("无法对 %s 切片", jqTypeName(t))
+		}
+	}
+	return out, nil
+}
+
+type jqIterate struct{ target jqNode }
+
+func (n *jqIterate) eval(input interface{}) ([]interface{}, error) {
+	targets, err := n.target.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, t := range targets {
+		switch v := t.(type) {
+		case nil:
+		case []interface{}:
+			out = append(out, v...)
+		case map[string]interface{}:
+			for _, k := range sortedMapKeys(v) {
+				out = append(out, v[k])
+			}
+		default:
+			return nil, errorf("无法迭代 %s", jqTypeName(t))
+		}
+	}
+	return out, nil
+}
+
+type jqTry struct{ body jqNode }
+
+func (n *jqTry) eval(input interface{}) ([]interface{}, error) {
+	out, err := n.body.eval(input)
+	if err != nil {
+		return nil, nil
+	}
+	return out, nil
+}
+
+type jqArray struct{ body jqNode }
+
+func (n *jqArray) eval(input interface{}) ([]interface{}, error) {
+	if n.body == nil {
+		return []interface{}{[]interface{}{}}, nil
+	}
+	items, err := n.body.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []interface{}{}
+	}
+	return []interface{}{items}, nil
+}
+
+type jqObject struct {
+	keys   []jqNode
+	values []jqNode
+}
+
+func (n *jqObject) eval(input interface{}) ([]interface{}, error) {
+	results := []map[string]interface{}{{}}
+	for i := range n.keys {
+		keys, err := n.keys[i].eval(input)
+		if err != nil {
+			return nil, err
+		}
+		values, err := n.values[i].eval(input)
+		if err != nil {
+			return nil, err
+		}
+		// 多个输出时生成笛卡尔积，与 jq 行为一致
+		var next []map[string]interface{}
+		for _, base := range results {
+			for _, k := range keys {
+				ks, ok := k.(string)
+				if !ok {
+					return nil, errorf("对象的键必须是字符串")
+				}
+				for _, v := range values {
+					obj := make(map[string]interface{}, len(base)+1)
+					for bk, bv := range base {
+						obj[bk] = bv
+					}
+					obj[ks] = v
+					next = append(next, obj)
+				}
+			}
+		}
+		results = next
+	}
+	out := make([]interface{}, len(results))
+	for i, r := range results {
+		out[i] = r
+	}
+	return out, nil
+}
+
+// ========== 内置函数 ==========
+
+type jqFunc struct {
+	name string
+	args []jqNode
+}
+
+// 支持的函数及参数个数
+var jqFuncArity = map[string]int{
+	"select": 1, "map": 1, "has": 1, "test": 1, "startswith": 1, "endswith": 1,
+	"contains": 1, "join": 1, "split": 1,
+	"length": 0, "keys": 0, "not": 0, "type": 0, "tostring": 0, "tonumber": 0,
+	"ascii_downcase": 0, "ascii_upcase": 0, "to_entries": 0, "first": 0, "last": 0,
+	"add": 0, "unique": 0, "sort": 0, "empty": 0, "values": 0,
+}
+
+func newJQFunc(name string, args []jqNode) (jqNode, error) {
+	arity, ok := jqFuncArity[name]
+	if !ok {
+		return nil, errorf("不支持的 jq 函数: %s", name)
+	}
+	if len(args) != arity {
+		return nil, errorf("jq 函数 %s 需要 %d 个参数", name, arity)
+	}
+	return &jqFunc{name: name, args: args}, nil
+}
+
+func (n *jqFunc) eval(input interface{}) ([]interface{}, error) {
+	switch n.name {
+	case "empty":
+		return nil, nil
+	case "select":
+		conds, err := n.args[0].eval(input)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, c := range conds {
+			if jqTruthy(c) {
+				out = append(out, input)
+			}
+		}
+		return out, nil
+	case "map":
+		items, err := (&jqIterate{target: jqIdentity{}}).eval(input)
+		if err != nil {
+			return nil, err
+		}
+		mapped := []interface{}{}
+		for _, item := range items {
+			vs, err := n.args[0].eval(item)
+			if err != nil {
+				return nil, err
+			}
+			mapped = append(mapped, vs...)
+		}
+		return []interface{}{mapped}, nil
+	case "values":
+		if input == nil {
+			return nil, nil
+		}
+		return []interface{}{input}, nil
+	}
+
+	if len(n.args) == 1 {
+		args, err := n.args[0].eval(input)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, arg := range args {
+			v, err := jqCall1(n.name, input, arg)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	}
+
+	v, err := jqCall0(n.name, input)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{v}, nil
+}
+
+func jqCall0(name string, input interface{}) (interface{}, error) {
+	switch name {
+	case "length":
+		switch v := input.(type) {
+		case nil:
+			return float64(0), nil
+		case string:
+			return float64(len([]rune(v))), nil
+		case []interface{}:
+			return float64(len(v)), nil
+		case map[string]interface{}:
+			return float64(len(v)), nil
+		}
+		if f, ok := toFloat(input); ok {
+			if f < 0 {
+				f = -f
+			}
+			return f, nil
+		}
+		return nil, errorf("%s 没有长度", jqTypeName(input))
+	case "keys":
+		switch v := input.(type) {
+		case map[string]interface{}:
+			var keys []interface{}
+			for _, k := range sortedMapKeys(v) {
+				keys = append(keys, k)
+			}
+			return keys, nil
+		case []interface{}:
+			keys := make([]interface{}, len(v))
+			for i := range v {
+				keys[i] = float64(i)
+			}
+			return keys, nil
+		}
+		return nil, errorf("%s 没有键", jqTypeName(input))
+	case "not":
+		return !jqTruthy(input), nil
+	case "type":
+		return jqTypeName(input), nil
+	case "tostring":
+		if s, ok := input.(string); ok {
+			return s, nil
+		}
+		b, err := json.Marshal(input)
+		return string(b), err
+	case "tonumber":
+		if f, ok := toFloat(input); ok {
+			return f, nil
+		}
+		s, ok := input.(string)
+		if !ok {
+			return nil, errorf("%s 无法转换为数字", jqTypeName(input))
+		}
+		return strconv.ParseFloat(s, 64)
+	case "ascii_downcase", "ascii_upcase":
+		s, ok := input.(string)
+		if !ok {
+			return nil, errorf("%s 需要字符串输入", name)
+		}
+		if name == "ascii_downcase" {
+			return strings.ToLower(s), nil
+		}
+		return strings.ToUpper(s), nil
+	case "to_entries":
+		m, ok := input.(map[string]interface{})
+		if !ok {
+			return nil, errorf("to_entries 需要对象输入")
+		}
+		var entries []interface{}
+		for _, k := range sortedMapKeys(m) {
+			entries = append(entries, map[string]interface{}{"key": k, "value": m[k]})
+		}
+		return entries, nil
+	case "first", "last":
+		list, ok := input.([]interface{})
+		if !ok {
+			return nil, errorf("%s 需要数组输入", name)
+		}
+		if len(list) == 0 {
+			return nil, nil
+		}
+		if name == "first" {
+			return list[0], nil
+		}
+		return list[len(list)-1], nil
+	case "add":
+		list, ok := input.([]interface{})
+		if !ok {
+			return nil, errorf("add 需要数组输入")
+		}
+		return jqAdd(list)
+	case "unique", "sort":
+		list, ok := input.([]interface{})
+		if !ok {
+			return nil, errorf("%s 需要数组输入", name)
+		}
+		sorted := append([]interface{}{}, list...)
+		sort.SliceStable(sorted, func(i, j int) bool { return jqLess(sorted[i], sorted[j]) })
+		if name == "sort" {
+			return sorted, nil
+		}
+		var uniq []interface{}
+		for i, v := range sorted {
+			if i == 0 || !valuesEqual(v, sorted[i-1]) {
+				uniq = append(uniq, v)
+			}
+		}
+		return uniq, nil
+	}
+	return nil, errorf("不支持的 jq 函数: %s", name)
+}
+
+func jqCall1(name string, input, arg interface{}) (interface{}, error) {
+	switch name {
+	case "has":
+		switch v := input.(type) {
+		case map[string]interface{}:
+			k, ok := arg.(string)
+			if !ok {
+				return nil, errorf("has 的参数必须是字符串")
+			}
+			_, exists := v[k]
+			return exists, nil
+		case []interface{}:
+			f, ok := toFloat(arg)
+			return ok && int(f) >= 0 && int(f) < len(v), nil
+		}
+		return nil, errorf("无法对 %s 调用 has", jqTypeName(input))
+	case "test", "startswith", "endswith", "split":
+		s, ok1 := input.(string)
+		a, ok2 := arg.(string)
+		if !ok1 || !ok2 {
+			return nil, errorf("%s 需要字符串输入和参数", name)
+		}
+		switch name {
+		case "test":
+			return matchRegexp(a, s), nil
+		case "startswith":
+			return strings.HasPrefix(s, a), nil
+		case "endswith":
+			return strings.HasSuffix(s, a), nil
+		default:
+			var parts []interface{}
+			for _, part := range strings.Split(s, a) {
+				parts = append(parts, part)
+			}
+			return parts, nil
+		}
+	case "contains":
+		return jqContains(input, arg), nil
+	case "join":
+		list, ok := input.([]interface{})
+		sep, ok2 := arg.(string)
+		if !ok || !ok2 {
+			return nil, errorf("join 需要数组输入和字符串参数")
+		}
+		parts := make([]string, len(list))
+		for i, item := range list {
+			if item != nil {
+				parts[i] = stringValue(item)
+			}
+		}
+		return strings.Join(parts, sep), nil
+	}
+	return nil, errorf("不支持的 jq 函数: %s", name)
+}
+
+func jqAdd(list []interface{}) (interface{}, error) {
+	var result interface{}
+	for _, item := range list {
+		if item == nil {
+			continue
+		}
+		if result == nil {
+			result = item
+			continue
+		}
+		switch r := result.(type) {
+		case string:
+			s, ok := item.(string)
+			if !ok {
+				return nil, errorf("add 无法将 %s 与字符串相加", jqTypeName(item))
+			}
+			result = r + s
+		case []interface{}:
+			l, ok := item.([]interface{})
+			if !ok {
+				return nil, errorf("add 无法将 %s 与数组相加", jqTypeName(item))
+			}
+			result = append(append([]interface{}{}, r...), l...)
+		default:
+			a, ok1 := toFloat(result)
+			b, ok2 := toFloat(item)
+			if !ok1 || !ok2 {
+				return nil, errorf("add 无法相加 %s 和 %s", jqTypeName(result), jqTypeName(item))
+			}
+			result = a + b
+		}
+	}
+	return result, nil
+}
+
+func jqContains(input, arg interface{}) bool {
+	switch v := input.(type) {
+	case string:
+		s, ok := arg.(string)
+		return ok && strings.Contains(v, s)
+	case []interface{}:
+		sub, ok := arg.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, want := range sub {
+			found := false
+			for _, have := range v {
+				if jqContains(have, want) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		sub, ok := arg.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, want := range sub {
+			have, exists := v[k]
+			if !exists || !jqContains(have, want) {
+				return false
+			}
+		}
+		return true
+	}
+	return valuesEqual(input, arg)
+}
+
+// jq 排序顺序: null < false < true < 数字 < 字符串 < 数组 < 对象
+func jqLess(a, b interface{}) bool {
+	ra, rb := jqTypeRank(a), jqTypeRank(b)
+	if ra != rb {
+		return ra < rb
+	}
+	if fa, ok := toFloat(a); ok {
+		fb, _ := toFloat(b)
+		return fa < fb
+	}
+	if sa, ok := a.(string); ok {
+		return sa < b.(string)
+	}
+	if ba, ok := a.(bool); ok {
+		return !ba && b.(bool)
+	}
+	ja, _ := json.Marshal(a)
+	jb, _ := json.Marshal(b)
+	return string(ja) < string(jb)
+}
+
+func jqTypeRank(v interface{}) int {
+	switch v := v.(type) {
+	case nil:
+		return 0
+	case bool:
+		if v {
+			return 2
+		}
+		return 1
+	case string:
+		return 4
+	case []interface{}:
+		return 5
+	case map[string]interface{}:
+		return 6
+	}
+	return 3
+}
+
+func jqTypeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	if _, ok := toFloat(v); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// jq 真值：只有 false 和 null 为假
+func jqTruthy(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+	if b, ok := v.(bool); ok {
+		return b
+	}
+	return true
+}
+
+// 正则缓存，避免对每个资源重复编译
+var regexpCache sync.Map
+
+func matchRegexp(pattern, s string) bool {
+	if cached, ok := regexpCache.Load(pattern); ok {
+		if re, ok := cached.(*regexp.Regexp); ok {
+			return re.MatchString(s)
+		}
+		return false
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		regexpCache.Store(pattern, err)
+		return false
+	}
+	regexpCache.Store(pattern, re)
+	return re.MatchString(s)
+}
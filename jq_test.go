@@ -0,0 +1,121 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJQEvaluate(t *testing.T) {
+	pod := testObject(t, jsonPathTestPod)
+	tests := []struct {
+		expr string
+		want string // 所有输出组成的 JSON 数组，空串表示没有输出
+	}{
+		{".kind", `["Pod"]`},
+		{".metadata.name", `["web-0"]`},
+		{`.metadata["name"]`, `["web-0"]`},
+		{`.metadata."name"`, `["web-0"]`},
+		{".metadata.missing", `[null]`},
+		{".metadata.missing.deeper", `[null]`},
+		{".spec.containers[0].name", `["app"]`},
+		{".spec.containers[-1].name", `["logger"]`},
+		{".spec.containers[1:] | map(.name)", `[["sidecar","logger"]]`},
+		{".spec.containers[:1] | length", `[1]`},
+		{".metadata.name[0:3]", `["web"]`},
+		{".spec.containers[].name", `["app","sidecar","logger"]`},
+		{".spec.containers[] | .image", `["nginx:1.25","envoy:1.29","fluent-bit:2.2"]`},
+		{".spec.containers[].ports[]?.containerPort", `[80,443,2020]`},
+		{".spec.volumes[]?", ""},
+		{".metadata.name, .kind", `["web-0","Pod"]`},
+		{".spec.containers[] | select(.name == \"sidecar\") | .image", `["envoy:1.29"]`},
+		{".spec.containers[] | select(.resources.limits.memory == null) | .name", `["app","logger"]`},
+		{".spec.containers[] | select(.ports) | .name", `["app","logger"]`},
+		{".spec.containers[] | select(.image | test(\"^(nginx|envoy):\")) | .name", `["app","sidecar"]`},
+		{".spec.containers[] | select(.name | startswith(\"s\") or endswith(\"r\")) | .name", `["sidecar","logger"]`},
+		{".spec.containers[] | select(has(\"ports\") and (.ports | length) > 1) | .name", `["app"]`},
+		{".spec.containers[] | select(.name != \"app\" | not) | .name", `["app"]`},
+		{".spec.containers | map(.name)", `[["app","sidecar","logger"]]`},
+		{".spec.containers | map(.name) | join(\",\")", `["app,sidecar,logger"]`},
+		{".spec.containers | map(.ports[]?.containerPort) | add", `[2543]`},
+		{"[.spec.containers[] | .name] | sort | first", `["app"]`},
+		{"[.spec.containers[].name] | length", `[3]`},
+		{".metadata.labels | keys", `[["app.kubernetes.io/name","tier"]]`},
+		{".metadata.labels | to_entries | map(.value)", `[["web","frontend"]]`},
+		{"{name: .metadata.name, phase: .status.phase}", `[{"name":"web-0","phase":"Running"}]`},
+		{"{kind, (.metadata.name): 1}", `[{"kind":"Pod","web-0":1}]`},
+		{"{image: .spec.containers[].image} | .image", `["nginx:1.25","envoy:1.29","fluent-bit:2.2"]`},
+		{".spec.nodeName // \"unscheduled\"", `["unscheduled"]`},
+		{".status.phase // \"unknown\"", `["Running"]`},
+		{".status.conditions[] | select(.status == \"False\") | .type", `["ContainersReady"]`},
+		{".metadata.name | ascii_upcase", `["WEB-0"]`},
+		{".metadata.name | split(\"-\")", `[["web","0"]]`},
+		{".metadata | has(\"labels\")", `[true]`},
+		{".spec.containers | contains([{name: \"app\"}])", `[true]`},
+		{".kind | type", `["string"]`},
+		{"-1, 2.5, true, null", `[-1,2.5,true,null]`},
+		{".metadata.name | empty", ""},
+	}
+	if node, err := compileJQ("."); err != nil {
+		t.Errorf("compileJQ(\".\") 返回错误: %v", err)
+	} else if got, _ := node.eval(pod); !reflect.DeepEqual(got, []interface{}{pod}) {
+		t.Errorf(". = %v, 期望输入本身", got)
+	}
+	for _, tt := range tests {
+		node, err := compileJQ(tt.expr)
+		if err != nil {
+			t.Errorf("compileJQ(%q) 返回错误: %v", tt.expr, err)
+			continue
+		}
+		got, err := node.eval(pod)
+		if err != nil {
+			t.Errorf("%s 求值出错: %v", tt.expr, err)
+			continue
+		}
+		if want := testValues(t, tt.want); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %v, 期望 %v", tt.expr, got, want)
+		}
+	}
+}
+
+func TestJQEvalErrors(t *testing.T) {
+	pod := testObject(t, jsonPathTestPod)
+	tests := []string{
+		".kind[0]",
+		".spec.containers.name",
+		".kind[]",
+		".spec.containers | to_entries",
+		".metadata.name | tonumber",
+	}
+	for _, expr := range tests {
+		node, err := compileJQ(expr)
+		if err != nil {
+			t.Errorf("compileJQ(%q) 返回错误: %v", expr, err)
+			continue
+		}
+		if got, err := node.eval(pod); err == nil {
+			t.Errorf("%s = %v, 期望求值出错", expr, got)
+		}
+	}
+}
+
+func TestJQCompileErrors(t *testing.T) {
+	tests := []string{
+		"",
+		".metadata |",
+		".spec.containers[",
+		"(.kind",
+		"{name: .metadata.name",
+		"{: 1}",
+		`"unterminated`,
+		"unknown_func",
+		"select",
+		"length(1)",
+		".kind ;",
+		".kind )",
+	}
+	for _, expr := range tests {
+		if _, err := compileJQ(expr); err == nil {
+			t.Errorf("compileJQ(%q) 期望返回错误", expr)
+		}
+	}
+}
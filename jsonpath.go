@@ -0,0 +1,868 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPath 步骤类型
+type jsonPathStepKind int
+
+const (
+	stepField jsonPathStepKind = iota
+	stepWildcard
+	stepNames
+	stepIndices
+	stepSlice
+	stepFilter
+)
+
+// 单个 JSONPath 步骤，recursive 表示 ".." 递归下降
+type jsonPathStep struct {
+	kind      jsonPathStepKind
+	recursive bool
+	name      string
+	names     []string
+	indices   []int
+	slice     [3]*int
+	filter    *jsonPathFilter
+}
+
+// 编译后的 JSONPath 表达式
+type jsonPath struct {
+	steps []jsonPathStep
+}
+
+// 过滤表达式节点：[?(@.a == "x" && @.b)]
+type jsonPathFilter struct {
+	op          string // "&&", "||", "!", 比较运算符，或 "" 表示存在性判断
+	left, right *jsonPathFilter
+	operand     *jsonPathOperand
+	compareTo   *jsonPathOperand
+}
+
+// 过滤表达式的操作数：相对路径 (@...) 或字面量
+type jsonPathOperand struct {
+	path    *jsonPath
+	literal interface{}
+}
+
+// JSONPath 模板节点：字面文本、{表达式} 或 {range 表达式}...{end} 循环
+type jsonPathTemplateNode struct {
+	text    string
+	path    *jsonPath
+	isRange bool
+	body    []jsonPathTemplateNode
+}
+
+// 编译后的 kubectl 风格 JSONPath 模板
+type jsonPathTemplate struct {
+	nodes []jsonPathTemplateNode
+	// 含字面文本或 range 时与 kubectl 一样将整个模板渲染为一段文本，否则逐个返回表达式的值
+	textOutput bool
+}
+
+// 编译 kubectl 风格的 JSONPath 模板，如 {.metadata.name}{"\t"}{.kind}、
+// {range .spec.containers[*]}{.name}={.image}{"\n"}{end}；不含花括号时视为单个表达式
+func compileJSONPathTemplate(input string) (*jsonPathTemplate, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, errorf("JSONPath 表达式为空")
+	}
+	if !strings.Contains(input, "{") {
+		p, err := compileJSONPath(input)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathTemplate{nodes: []jsonPathTemplateNode{{path: p}}}, nil
+	}
+
+	t := &jsonPathTemplate{}
+	// 未闭合的 range 节点，body 先收集到栈顶，遇到 {end} 时挂到 range 节点上
+	var stack []jsonPathTemplateNode
+	nodes := &t.nodes
+	for input != "" {
+		start := strings.Index(input, "{")
+		if start < 0 {
+			start = len(input)
+		}
+		if start > 0 {
+			*nodes = append(*nodes, jsonPathTemplateNode{text: input[:start]})
+			t.textOutput = true
+			input = input[start:]
+			continue
+		}
+		end := matchingBrace(input, 0)
+		if end < 0 {
+			return nil, errorf("JSONPath 模板中的 { 未闭合")
+		}
+		expr := strings.TrimSpace(input[1:end])
+		input = input[end+1:]
+
+		switch {
+		case expr == "end":
+			if len(stack) == 0 {
+				return nil, errorf("JSONPath 模板中的 {end} 没有对应的 {range}")
+			}
+			stack[len(stack)-1].body = *nodes
+			node := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			nodes = &t.nodes
+			if len(stack) > 0 {
+				nodes = &stack[len(stack)-1].body
+			}
+			*nodes = append(*nodes, node)
+		case expr == "range" || strings.HasPrefix(expr, "range ") || strings.HasPrefix(expr, "range\t"):
+			p, err := compileJSONPath(strings.TrimSpace(expr[len("range"):]))
+			if err != nil {
+				return nil, err
+			}
+			t.textOutput = true
+			stack = append(stack, jsonPathTemplateNode{path: p, isRange: true})
+			nodes = &stack[len(stack)-1].body
+		case strings.HasPrefix(expr, "\"") || strings.HasPrefix(expr, "'"):
+			text, err := unquoteJSONPathLiteral(expr)
+			if err != nil {
+				return nil, err
+			}
+			*nodes = append(*nodes, jsonPathTemplateNode{text: text})
+			t.textOutput = true
+		default:
+			p, err := compileJSONPath(expr)
+			if err != nil {
+				return nil, err
+			}
+			*nodes = append(*nodes, jsonPathTemplateNode{path: p})
+		}
+	}
+	if len(stack) > 0 {
+		return nil, errorf("JSONPath 模板中的 {range} 缺少 {end}")
+	}
+	return t, nil
+}
+
+// 模板中 {"..."} 形式的字面量：双引号按 Go 字符串转义（\t、\n 等），单引号原样
+func unquoteJSONPathLiteral(s string) (string, error) {
+	if s[0] == '"' {
+		text, err := strconv.Unquote(s)
+		if err != nil {
+			return "", errorf("无效的字符串: %s", s)
+		}
+		return text, nil
+	}
+	return unquoteJSONPathString(s)
+}
+
+// 对输入对象求值。只含表达式的模板返回各表达式的全部匹配值；
+// 含字面文本或 range 的模板返回渲染后的文本，结果为空时不返回值
+func (t *jsonPathTemplate) Evaluate(input interface{}) []interface{} {
+	if !t.textOutput {
+		var out []interface{}
+		for _, node := range t.nodes {
+			out = append(out, node.path.Evaluate(input)...)
+		}
+		return out
+	}
+	var b strings.Builder
+	renderJSONPathNodes(&b, t.nodes, input)
+	if b.Len() == 0 {
+		return nil
+	}
+	return []interface{}{b.String()}
+}
+
+// 按 kubectl 的规则渲染：同一表达式的多个值以空格分隔，range 对每个匹配值求值循环体
+func renderJSONPathNodes(b *strings.Builder, nodes []jsonPathTemplateNode, input interface{}) {
+	for _, node := range nodes {
+		switch {
+		case node.isRange:
+			for _, v := range node.path.Evaluate(input) {
+				renderJSONPathNodes(b, node.body, v)
+			}
+		case node.path != nil:
+			for i, v := range node.path.Evaluate(input) {
+				if i > 0 {
+					b.WriteByte(' ')
+				}
+				b.WriteString(jsonPathText(v))
+			}
+		default:
+			b.WriteString(node.text)
+		}
+	}
+}
+
+// 值的文本形式：对象和数组输出 JSON，其余与 kubectl 一致直接输出
+func jsonPathText(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+	return stringValue(v)
+}
+
+// 找到与 start 处 { 匹配的 }，忽略引号中的字符
+func matchingBrace(s string, start int) int {
+	depth := 0
+	var quote byte
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"':
+			quote = c
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// 编译单个 JSONPath 表达式（不含外层花括号）
+func compileJSONPath(expr string) (*jsonPath, error) {
+	p := &jsonPath{}
+	s := strings.TrimSpace(expr)
+	s = strings.TrimPrefix(s, "$")
+	s = strings.TrimPrefix(s, "@")
+	i := 0
+
+	// 允许省略开头的点，如 spec.replicas
+	if i < len(s) && s[i] != '.' && s[i] != '[' {
+		s = "." + s
+	}
+
+	for i < len(s) {
+		recursive := false
+		switch s[i] {
+		case '.':
+			i++
+			if i < len(s) && s[i] == '.' {
+				recursive = true
+				i++
+			}
+			if i < len(s) && s[i] == '[' {
+				step, next, err := parseJSONPathBracket(s, i)
+				if err != nil {
+					return nil, err
+				}
+				step.recursive = recursive
+				p.steps = append(p.steps, step)
+				i = next
+				continue
+			}
+			if i < len(s) && s[i] == '*' {
+				p.steps = append(p.steps, jsonPathStep{kind: stepWildcard, recursive: recursive})
+				i++
+				continue
+			}
+			name, next := readJSONPathIdent(s, i)
+			if name == "" {
+				if i >= len(s) && !recursive {
+					// 单独的 "." 表示根对象
+					return p, nil
+				}
+				return nil, errorf("JSONPath 第 %d 个字符附近缺少字段名", i+1)
+			}
+			p.steps = append(p.steps, jsonPathStep{kind: stepField, name: name, recursive: recursive})
+			i = next
+		case '[':
+			step, next, err := parseJSONPathBracket(s, i)
+			if err != nil {
+				return nil, err
+			}
+			p.steps = append(p.steps, step)
+			i = next
+		case ' ', '\t':
+			i++
+		default:
+			return nil, errorf("JSONPath 第 %d 个字符 %q 无法识别", i+1, s[i])
+		}
+	}
+	return p, nil
+}
+
+// 读取字段名，支持 \. 转义（如 app\.kubernetes\.io/name）
+func readJSONPathIdent(s string, i int) (string, int) {
+	var b strings.Builder
+	for i < len(s) {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) {
+			b.WriteByte(s[i+1])
+			i += 2
+			continue
+		}
+		if c == '.' || c == '[' || c == ' ' || c == ')' || c == '=' || c == '!' || c == '<' || c == '>' || c == '&' || c == '|' {
+			break
+		}
+		b.WriteByte(c)
+		i++
+	}
+	return b.String(), i
+}
+
+// 解析 [...] 步骤，返回步骤和 ] 之后的位置
+func parseJSONPathBracket(s string, start int) (jsonPathStep, int, error) {
+	end := -1
+	depth := 0
+	var quote byte
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"':
+			quote = c
+		case '[', '(':
+			depth++
+		case ']', ')':
+			depth--
+			if depth == 0 && c == ']' {
+				end = i
+			}
+		}
+		if end >= 0 {
+			break
+		}
+	}
+	if end < 0 {
+		return jsonPathStep{}, 0, errorf("JSONPath 中的 [ 未闭合")
+	}
+
+	content := strings.TrimSpace(s[start+1 : end])
+	next := end + 1
+
+	switch {
+	case content == "*":
+		return jsonPathStep{kind: stepWildcard}, next, nil
+	case strings.HasPrefix(content, "?(") && strings.HasSuffix(content, ")"):
+		filter, err := parseJSONPathFilter(content[2 : len(content)-1])
+		if err != nil {
+			return jsonPathStep{}, 0, err
+		}
+		return jsonPathStep{kind: stepFilter, filter: filter}, next, nil
+	case strings.HasPrefix(content, "'") || strings.HasPrefix(content, "\""):
+		var names []string
+		for _, part := range splitOutsideQuotes(content, ',') {
+			name, err := unquoteJSONPathString(strings.TrimSpace(part))
+			if err != nil {
+				return jsonPathStep{}, 0, err
+			}
+			names = append(names, name)
+		}
+		return jsonPathStep{kind: stepNames, names: names}, next, nil
+	case strings.Contains(content, ":"):
+		var step jsonPathStep
+		step.kind = stepSlice
+		parts := strings.Split(content, ":")
+		if len(parts) > 3 {
+			return jsonPathStep{}, 0, errorf("无效的切片: [%s]", content)
+		}
+		for i, part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return jsonPathStep{}, 0, errorf("无效的切片: [%s]", content)
+			}
+			step.slice[i] = &n
+		}
+		return step, next, nil
+	default:
+		var step jsonPathStep
+		step.kind = stepIndices
+		for _, part := range strings.Split(content, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				// 兼容 [name] 形式的字段访问
+				return jsonPathStep{kind: stepNames, names: []string{content}}, next, nil
+			}
+			step.indices = append(step.indices, n)
+		}
+		return step, next, nil
+	}
+}
+
+// 按分隔符拆分，忽略引号内的分隔符
+func splitOutsideQuotes(s string, sep byte) []string {
+	var parts []string
+	var quote byte
+	last := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		if c == '\'' || c == '"' {
+			quote = c
+		} else if c == sep {
+			parts = append(parts, s[last:i])
+			last = i + 1
+		}
+	}
+	return append(parts, s[last:])
+}
+
+func unquoteJSONPathString(s string) (string, error) {
+	if len(s) < 2 || (s[0] != '\'' && s[0] != '"') || s[len(s)-1] != s[0] {
+		return "", errorf("无效的字符串: %s", s)
+	}
+	return s[1 : len(s)-1], nil
+}
+
+// ========== 过滤表达式解析 ==========
+
+type jsonPathFilterParser struct {
+	s   string
+	pos int
+}
+
+func parseJSONPathFilter(expr string) (*jsonPathFilter, error) {
+	p := &jsonPathFilterParser{s: expr}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.s) {
+		return nil, errorf("过滤表达式多余的内容: %s", p.s[p.pos:])
+	}
+	return f, nil
+}
+
+func (p *jsonPathFilterParser) skipSpaces() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *jsonPathFilterParser) consume(token string) bool {
+	p.skipSpaces()
+	if strings.HasPrefix(p.s[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *jsonPathFilterParser) parseOr() (*jsonPathFilter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &jsonPathFilter{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *jsonPathFilterParser) parseAnd() (*jsonPathFilter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &jsonPathFilter{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *jsonPathFilterParser) parseUnary() (*jsonPathFilter, error) {
+	p.skipSpaces()
+	if strings.HasPrefix(p.s[p.pos:], "!") && !strings.HasPrefix(p.s[p.pos:], "!=") {
+		p.pos++
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &jsonPathFilter{op: "!", left: inner}, nil
+	}
+	if p.consume("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(")") {
+			return nil, errorf("过滤表达式缺少 )")
+		}
+		return inner, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">", "=~"} {
+		if p.consume(op) {
+			right, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			return &jsonPathFilter{op: op, operand: left, compareTo: right}, nil
+		}
+	}
+	return &jsonPathFilter{operand: left}, nil
+}
+
+func (p *jsonPathFilterParser) parseOperand() (*jsonPathOperand, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.s) {
+		return nil, errorf("过滤表达式不完整")
+	}
+	rest := p.s[p.pos:]
+
+	switch {
+	case rest[0] == '@':
+		end := 1
+		depth := 0
+		for end < len(rest) {
+			c := rest[end]
+			if c == '[' {
+				depth++
+			} else if c == ']' {
+				depth--
+			} else if depth == 0 && strings.IndexByte(" =!<>&|)", c) >= 0 {
+				break
+			}
+			end++
+		}
+		path, err := compileJSONPath(rest[:end])
+		if err != nil {
+			return nil, err
+		}
+		p.pos += end
+		return &jsonPathOperand{path: path}, nil
+	case rest[0] == '\'' || rest[0] == '"':
+		end := strings.IndexByte(rest[1:], rest[0])
+		if end < 0 {
+			return nil, errorf("过滤表达式中的字符串未闭合")
+		}
+		p.pos += end + 2
+		return &jsonPathOperand{literal: rest[1 : end+1]}, nil
+	default:
+		end := 0
+		for end < len(rest) && strings.IndexByte(" =!<>&|)", rest[end]) < 0 {
+			end++
+		}
+		word := rest[:end]
+		p.pos += end
+		switch word {
+		case "true":
+			return &jsonPathOperand{literal: true}, nil
+		case "false":
+			return &jsonPathOperand{literal: false}, nil
+		case "null":
+			return &jsonPathOperand{literal: nil}, nil
+		}
+		n, err := strconv.ParseFloat(word, 64)
+		if err != nil {
+			return nil, errorf("过滤表达式中无法识别的值: %s", word)
+		}
+		return &jsonPathOperand{literal: n}, nil
+	}
+}
+
+// ========== 求值 ==========
+
+// 对输入对象求值，返回所有匹配值
+func (p *jsonPath) Evaluate(input interface{}) []interface{} {
+	current := []interface{}{input}
+	for _, step := range p.steps {
+		var next []interface{}
+		for _, value := range current {
+			if step.recursive {
+				for _, d := range descendants(value) {
+					next = append(next, step.apply(d)...)
+				}
+			} else {
+				next = append(next, step.apply(value)...)
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// 返回节点自身及所有后代
+func descendants(value interface{}) []interface{} {
+	out := []interface{}{value}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedMapKeys(v) {
+			out = append(out, descendants(v[k])...)
+		}
+	case []interface{}:
+		for _, item := range v {
+			out = append(out, descendants(item)...)
+		}
+	}
+	return out
+}
+
+func sortedMapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (step jsonPathStep) apply(value interface{}) []interface{} {
+	switch step.kind {
+	case stepField:
+		if m, ok := value.(map[string]interface{}); ok {
+			if v, exists := m[step.name]; exists {
+				return []interface{}{v}
+			}
+		}
+	case stepNames:
+		if m, ok := value.(map[string]interface{}); ok {
+			var out []interface{}
+			for _, name := range step.names {
+				if v, exists := m[name]; exists {
+					out = append(out, v)
+				}
+			}
+			return out
+		}
+	case stepWildcard:
+		switch v := value.(type) {
+		case map[string]interface{}:
+			var out []interface{}
+			for _, k := range sortedMapKeys(v) {
+				out = append(out, v[k])
+			}
+			return out
+		case []interface{}:
+			return v
+		}
+	case stepIndices:
+		if list, ok := value.([]interface{}); ok {
+			var out []interface{}
+			for _, i := range step.indices {
+				if i < 0 {
+					i += len(list)
+				}
+				if i >= 0 && i < len(list) {
+					out = append(out, list[i])
+				}
+			}
+			return out
+		}
+	case stepSlice:
+		if list, ok := value.([]interface{}); ok {
+			return applySlice(list, step.slice)
+		}
+	case stepFilter:
+		var items []interface{}
+		switch v := value.(type) {
+		case []interface{}:
+			items = v
+		case map[string]interface{}:
+			for _, k := range sortedMapKeys(v) {
+				items = append(items, v[k])
+			}
+		}
+		var out []interface{}
+		for _, item := range items {
+			if step.filter.match(item) {
+				out = append(out, item)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func applySlice(list []interface{}, bounds [3]*int) []interface{} {
+	n := len(list)
+	start, end, stepSize := 0, n, 1
+	if bounds[2] != nil {
+		stepSize = *bounds[2]
+	}
+	if stepSize <= 0 {
+		return nil
+	}
+	if bounds[0] != nil {
+		start = *bounds[0]
+		if start < 0 {
+			start += n
+		}
+	}
+	if bounds[1] != nil {
+		end = *bounds[1]
+		if end < 0 {
+			end += n
+		}
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end > n {
+		end = n
+	}
+	var out []interface{}
+	for i := start; i < end; i += stepSize {
+		out = append(out, list[i])
+	}
+	return out
+}
+
+func (f *jsonPathFilter) match(item interface{}) bool {
+	switch f.op {
+	case "&&":
+		return f.left.match(item) && f.right.match(item)
+	case "||":
+		return f.left.match(item) || f.right.match(item)
+	case "!":
+		return !f.left.match(item)
+	case "":
+		values := f.operand.values(item)
+		for _, v := range values {
+			if v != nil && v != false {
+				return true
+			}
+		}
+		return false
+	}
+
+	left := f.operand.values(item)
+	right := f.compareTo.values(item)
+	if len(left) == 0 {
+		left = []interface{}{nil}
+	}
+	if len(right) == 0 {
+		right = []interface{}{nil}
+	}
+	for _, l := range left {
+		for _, r := range right {
+			if compareValues(l, r, f.op) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (o *jsonPathOperand) values(item interface{}) []interface{} {
+	if o.path != nil {
+		return o.path.Evaluate(item)
+	}
+	return []interface{}{o.literal}
+}
+
+// 转换为 float64，非数字返回 false
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// 按运算符比较两个值，数字按数值比较，其余按字符串比较
+func compareValues(l, r interface{}, op string) bool {
+	if op == "=~" {
+		ls, ok1 := l.(string)
+		rs, ok2 := r.(string)
+		return ok1 && ok2 && matchRegexp(rs, ls)
+	}
+
+	lf, lok := toFloat(l)
+	rf, rok := toFloat(r)
+	if lok && rok {
+		switch op {
+		case "==":
+			return lf == rf
+		case "!=":
+			return lf != rf
+		case "<":
+			return lf < rf
+		case "<=":
+			return lf <= rf
+		case ">":
+			return lf > rf
+		case ">=":
+			return lf >= rf
+		}
+		return false
+	}
+
+	switch op {
+	case "==":
+		return valuesEqual(l, r)
+	case "!=":
+		return !valuesEqual(l, r)
+	}
+
+	ls, ok1 := l.(string)
+	rs, ok2 := r.(string)
+	if !ok1 || !ok2 {
+		return false
+	}
+	switch op {
+	case "<":
+		return ls < rs
+	case "<=":
+		return ls <= rs
+	case ">":
+		return ls > rs
+	case ">=":
+		return ls >= rs
+	}
+	return false
+}
+
+// 深度比较，数字类型统一按数值比较
+func valuesEqual(l, r interface{}) bool {
+	lf, lok := toFloat(l)
+	rf, rok := toFloat(r)
+	if lok && rok {
+		return lf == rf
+	}
+	return reflect.DeepEqual(l, r)
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// 测试用的 Pod 对象，数字与 YAML/JSON 解析结果一致为 float64
+const jsonPathTestPod = `{
+  "kind": "Pod",
+  "metadata": {
+    "name": "web-0",
+    "namespace": "default",
+    "labels": {"app.kubernetes.io/name": "web", "tier": "frontend"}
+  },
+  "spec": {
+    "containers": [
+      {"name": "app", "image": "nginx:1.25", "ports": [{"containerPort": 80}, {"containerPort": 443}]},
+      {"name": "sidecar", "image": "envoy:1.29", "resources": {"limits": {"memory": "128Mi"}}},
+      {"name": "logger", "image": "fluent-bit:2.2", "ports": [{"containerPort": 2020}]}
+    ]
+  },
+  "status": {
+    "phase": "Running",
+    "conditions": [
+      {"type": "Ready", "status": "True"},
+      {"type": "ContainersReady", "status": "False"}
+    ]
+  }
+}`
+
+func testObject(t *testing.T, data string) interface{} {
+	t.Helper()
+	var obj interface{}
+	if err := json.Unmarshal([]byte(data), &obj); err != nil {
+		t.Fatalf("解析测试数据失败: %v", err)
+	}
+	return obj
+}
+
+// 将期望值写成 JSON 数组，便于与求值结果比较
+func testValues(t *testing.T, data string) []interface{} {
+	t.Helper()
+	if data == "" {
+		return nil
+	}
+	return testObject(t, data).([]interface{})
+}
+
+func TestJSONPathEvaluate(t *testing.T) {
+	pod := testObject(t, jsonPathTestPod)
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"{.metadata.name}", `["web-0"]`},
+		{".metadata.name", `["web-0"]`},
+		{"metadata.name", `["web-0"]`},
+		{"$.kind", `["Pod"]`},
+		{"{.metadata['name']}", `["web-0"]`},
+		{`{.metadata["name","namespace"]}`, `["web-0","default"]`},
+		{`{.metadata.labels.app\.kubernetes\.io/name}`, `["web"]`},
+		{"{.metadata.missing}", ""},
+		{"{.spec.containers[0].name}", `["app"]`},
+		{"{.spec.containers[-1].name}", `["logger"]`},
+		{"{.spec.containers[0,2].name}", `["app","logger"]`},
+		{"{.spec.containers[5].name}", ""},
+		{"{.spec.containers[*].name}", `["app","sidecar","logger"]`},
+		{"{.spec.containers.*.name}", `["app","sidecar","logger"]`},
+		{"{.spec.containers[1:].name}", `["sidecar","logger"]`},
+		{"{.spec.containers[:2].name}", `["app","sidecar"]`},
+		{"{.spec.containers[::2].name}", `["app","logger"]`},
+		{"{.spec.containers[-2:].name}", `["sidecar","logger"]`},
+		{"{.spec.containers[0:0].name}", ""},
+		{"{..image}", `["nginx:1.25","envoy:1.29","fluent-bit:2.2"]`},
+		{"{..containerPort}", `[80,443,2020]`},
+		{"{.spec..ports[0].containerPort}", `[80,2020]`},
+		{`{.spec.containers[?(@.name=="sidecar")].image}`, `["envoy:1.29"]`},
+		{`{.spec.containers[?(@.name != 'sidecar')].name}`, `["app","logger"]`},
+		{`{.spec.containers[?(@.resources.limits.memory)].name}`, `["sidecar"]`},
+		{`{.spec.containers[?(!@.ports)].name}`, `["sidecar"]`},
+		{`{.spec.containers[?(@.image =~ "^(nginx|envoy):")].name}`, `["app","sidecar"]`},
+		{`{.spec.containers[?(@.ports[*].containerPort > 1000)].name}`, `["logger"]`},
+		{`{.spec.containers[?(@.ports[0].containerPort == 80 || @.name == "logger")].name}`, `["app","logger"]`},
+		{`{.spec.containers[?(@.ports && @.name != "app")].name}`, `["logger"]`},
+		{`{.status.conditions[?(@.status=="False")].type}`, `["ContainersReady"]`},
+		{"{.metadata.name}{.kind}", `["web-0","Pod"]`},
+		{"{.spec.containers[*].ports}", `[[{"containerPort":80},{"containerPort":443}],[{"containerPort":2020}]]`},
+	}
+	for _, tt := range tests {
+		tmpl, err := compileJSONPathTemplate(tt.expr)
+		if err != nil {
+			t.Errorf("compileJSONPathTemplate(%q) 返回错误: %v", tt.expr, err)
+			continue
+		}
+		got := tmpl.Evaluate(pod)
+		if want := testValues(t, tt.want); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %v, 期望 %v", tt.expr, got, want)
+		}
+	}
+}
+
+func TestJSONPathTemplateText(t *testing.T) {
+	pod := testObject(t, jsonPathTestPod)
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"{.metadata.name}/{.kind}", "web-0/Pod"},
+		{`{.metadata.namespace}{"\t"}{.metadata.name}`, "default\tweb-0"},
+		{`{.metadata.name}{"\n"}`, "web-0\n"},
+		{`{'a\tb'}`, `a\tb`},
+		{`{"{}"}{.kind}`, "{}Pod"},
+		{`{"\""}{.kind}{"\""}`, `"Pod"`},
+		{"name: {.metadata.name}", "name: web-0"},
+		{"images: {.spec.containers[*].image}", "images: nginx:1.25 envoy:1.29 fluent-bit:2.2"},
+		{"{.metadata.labels.tier} {.spec.containers[0].ports[0]}", `frontend {"containerPort":80}`},
+		{`{range .spec.containers[*]}{.name}={.image}{"\n"}{end}`, "app=nginx:1.25\nsidecar=envoy:1.29\nlogger=fluent-bit:2.2\n"},
+		{`{range .spec.containers[*]}[{.name}]{end}`, "[app][sidecar][logger]"},
+		{`{range .spec.containers[*]}{.name}:{range .ports[*]}{" "}{.containerPort}{end};{end}`, "app: 80 443;sidecar:;logger: 2020;"},
+		{`{range .status.conditions[?(@.status=="True")]}{.type}{end}`, "Ready"},
+		{`{range .spec.containers[*]}{@.name},{end}`, "app,sidecar,logger,"},
+		{`{range .spec.missing[*]}{.name}{end}`, ""},
+		{"{.metadata.missing}-", "-"},
+	}
+	for _, tt := range tests {
+		tmpl, err := compileJSONPathTemplate(tt.expr)
+		if err != nil {
+			t.Errorf("compileJSONPathTemplate(%q) 返回错误: %v", tt.expr, err)
+			continue
+		}
+		got := tmpl.Evaluate(pod)
+		var want []interface{}
+		if tt.want != "" {
+			want = []interface{}{tt.want}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %q, 期望 %q", tt.expr, got, want)
+		}
+	}
+}
+
+func TestJSONPathCompileErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"   ",
+		"{.metadata.name",
+		"{.spec.containers[0}",
+		"{.spec.containers[1:2:3:4]}",
+		"{.spec.containers[a:b]}",
+		"{.metadata..}",
+		"{.spec.containers[?(@.name == )]}",
+		`{.spec.containers[?(@.name == "x)]}`,
+		"{.spec.containers[?(@.name == unknown)]}",
+		"{range .spec.containers[*]}{.name}",
+		"{.name}{end}",
+		`{"\q"}`,
+		"{'unterminated}",
+	}
+	for _, expr := range tests {
+		if _, err := compileJSONPathTemplate(expr); err == nil {
+			t.Errorf("compileJSONPathTemplate(%q) 期望返回错误", expr)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// 推荐标签 https://kubernetes.io/docs/concepts/overview/working-with-objects/common-labels/
+var recommendedLabels = []string{
+	"app.kubernetes.io/name",
+	"app.kubernetes.io/instance",
+	"app.kubernetes.io/version",
+	"app.kubernetes.io/component",
+	"app.kubernetes.io/part-of",
+	"app.kubernetes.io/managed-by",
+}
+
+// 旧式标签与推荐标签的对应关系，两者混用视为不一致
+var legacyLabelAliases = map[string]string{
+	"app":       "app.kubernetes.io/name",
+	"name":      "app.kubernetes.io/name",
+	"version":   "app.kubernetes.io/version",
+	"component": "app.kubernetes.io/component",
+	"release":   "app.kubernetes.io/instance",
+	"instance":  "app.kubernetes.io/instance",
+	"part-of":   "app.kubernetes.io/part-of",
+}
+
+// 需要检查推荐标签的工作负载类型
+var labelCheckedKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+	"Job":         true,
+	"CronJob":     true,
+	"Service":     true,
+}
+
+// 注解值在索引中的最大展示字符数
+const annotationPreviewLength = 80
+
+// 注解值在索引中最多展示的个数
+const annotationValueLimit = 10
+
+type LabelValueStat struct {
+	Value    string
+	Count    int
+	Selector string
+}
+
+type LabelKeyStat struct {
+	Key         string
+	Count       int
+	Kinds       []string
+	Values      []LabelValueStat
+	MoreValues  int
+	Recommended bool
+}
+
+// 标签不一致问题
+type LabelInconsistency struct {
+	Type    string
+	Message string
+	Keys    []string
+	Index   int // 关联资源的索引，-1 表示与具体资源无关
+	Kind    string
+	Name    string
+	Ns      string
+}
+
+// 缺少推荐标签的资源
+type MissingLabels struct {
+	Index     int
+	Kind      string
+	Namespace string
+	Name      string
+	Missing   []string
+}
+
+type LabelIndex struct {
+	Labels             []LabelKeyStat
+	Annotations        []LabelKeyStat
+	Inconsistencies    []LabelInconsistency
+	MissingRecommended []MissingLabels
+}
+
+type keyAccumulator struct {
+	count  int
+	kinds  map[string]bool
+	values map[string]int
+}
+
+func accumulate(acc map[string]*keyAccumulator, values map[string]string, kind string) {
+	for k, v := range values {
+		a, ok := acc[k]
+		if !ok {
+			a = &keyAccumulator{kinds: make(map[string]bool), values: make(map[string]int)}
+			acc[k] = a
+		}
+		a.count++
+		a.kinds[kind] = true
+		a.values[v]++
+	}
+}
+
+// 将累加结果转换为按出现次数排序的列表
+func keyStats(acc map[string]*keyAccumulator, annotations bool) []LabelKeyStat {
+	var stats []LabelKeyStat
+	for key, a := range acc {
+		stat := LabelKeyStat{Key: key, Count: a.count, Recommended: containsString(recommendedLabels, key)}
+		for kind := range a.kinds {
+			stat.Kinds = append(stat.Kinds, kind)
+		}
+		sort.Strings(stat.Kinds)
+
+		for value, count := range a.values {
+			display := value
+			// 按字符截断，避免截断多字节字符产生无效的 UTF-8
+			if annotations && utf8.RuneCountInString(display) > annotationPreviewLength {
+				display = string([]rune(display)[:annotationPreviewLength]) + "…"
+			}
+			stat.Values = append(stat.Values, LabelValueStat{Value: display, Count: count, Selector: key + "=" + value})
+		}
+		sort.Slice(stat.Values, func(i, j int) bool {
+			if stat.Values[i].Count != stat.Values[j].Count {
+				return stat.Values[i].Count > stat.Values[j].Count
+			}
+			return stat.Values[i].Value < stat.Values[j].Value
+		})
+		if annotations && len(stat.Values) > annotationValueLimit {
+			stat.MoreValues = len(stat.Values) - annotationValueLimit
+			stat.Values = stat.Values[:annotationValueLimit]
+		}
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		return stats[i].Key < stats[j].Key
+	})
+	return stats
+}
+
+// 建立标签和注解索引，并检查不一致与缺失的推荐标签
+func buildLabelIndex(infos []ResourceInfo, lang string) LabelIndex {
+	labelAcc := make(map[string]*keyAccumulator)
+	annotationAcc := make(map[string]*keyAccumulator)
+	var index LabelIndex
+
+	legacyKeys := make([]string, 0, len(legacyLabelAliases))
+	for legacy := range legacyLabelAliases {
+		legacyKeys = append(legacyKeys, legacy)
+	}
+	sort.Strings(legacyKeys)
+
+	for i, info := range infos {
+		labels := resourceLabels(info)
+		accumulate(labelAcc, labels, info.Kind)
+		accumulate(annotationAcc, resourceAnnotations(info), info.Kind)
+
+		// 同一资源上旧式标签与推荐标签取值不同
+		for _, legacy := range legacyKeys {
+			recommended := legacyLabelAliases[legacy]
+			lv, hasLegacy := labels[legacy]
+			rv, hasRecommended := labels[recommended]
+			if hasLegacy && hasRecommended && lv != rv {
+				index.Inconsistencies = append(index.Inconsistencies, LabelInconsistency{
+					Type:    tr(lang, "取值冲突"),
+					Message: tr(lang, "%s=%s 与 %s=%s 不一致", legacy, lv, recommended, rv),
+					Keys:    []string{legacy, recommended},
+					Index:   i,
+					Kind:    info.Kind,
+					Name:    info.Name,
+					Ns:      info.Namespace,
+				})
+			}
+		}
+
+		if labelCheckedKinds[info.Kind] {
+			var missing []string
+			for _, key := range recommendedLabels {
+				if _, ok := labels[key]; !ok {
+					missing = append(missing, key)
+				}
+			}
+			if len(missing) > 0 {
+				index.MissingRecommended = append(index.MissingRecommended, MissingLabels{
+					Index:     i,
+					Kind:      info.Kind,
+					Namespace: info.Namespace,
+					Name:      info.Name,
+					Missing:   missing,
+				})
+			}
+		}
+	}
+
+	// 旧式标签与推荐标签在不同资源间混用
+	for _, legacy := range legacyKeys {
+		recommended := legacyLabelAliases[legacy]
+		if labelAcc[legacy] != nil && labelAcc[recommended] != nil {
+			index.Inconsistencies = append(index.Inconsistencies, LabelInconsistency{
+				Type:    tr(lang, "约定混用"),
+				Message: tr(lang, "同时使用旧式标签 %s（%d 个资源）和推荐标签 %s（%d 个资源）", legacy, labelAcc[legacy].count, recommended, labelAcc[recommended].count),
+				Keys:    []string{legacy, recommended},
+				Index:   -1,
+			})
+		}
+	}
+
+	// 仅大小写不同的标签键
+	byLower := make(map[string][]string)
+	for key := range labelAcc {
+		byLower[strings.ToLower(key)] = append(byLower[strings.ToLower(key)], key)
+	}
+	var lowerKeys []string
+	for lower, keys := range byLower {
+		if len(keys) > 1 {
+			lowerKeys = append(lowerKeys, lower)
+		}
+	}
+	sort.Strings(lowerKeys)
+	for _, lower := range lowerKeys {
+		keys := byLower[lower]
+		sort.Strings(keys)
+		index.Inconsistencies = append(index.Inconsistencies, LabelInconsistency{
+			Type:    tr(lang, "大小写不一致"),
+			Message: tr(lang, "标签键仅大小写不同: %s", strings.Join(keys, tr(lang, "、"))),
+			Keys:    keys,
+			Index:   -1,
+		})
+	}
+
+	sort.SliceStable(index.Inconsistencies, func(i, j int) bool {
+		return index.Inconsistencies[i].Index < index.Inconsistencies[j].Index
+	})
+
+	index.Labels = keyStats(labelAcc, false)
+	index.Annotations = keyStats(annotationAcc, true)
+	return index
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// 检查结果的严重程度
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+	severityInfo    = "info"
+)
+
+// 严重程度排序，数值越小越严重
+var severityRank = map[string]int{
+	severityError:   0,
+	severityWarning: 1,
+	severityInfo:    2,
+}
+
+// 一条检查结果
+type Finding struct {
+	Rule      string `json:"rule"`
+	Severity  string `json:"severity"`
+	Message   string `json:"message"`
+	Index     int    `json:"index"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// 检查规则，Check 对单个资源返回问题描述
+type LintRule struct {
+	ID          string
+	Severity    string
+	Description string
+	Check       func(ctx *lintContext, info ResourceInfo) []string `json:"-"`
+}
+
+// 规则执行时可访问的其他资源，以及问题描述使用的语言
+type lintContext struct {
+	pdbs []ResourceInfo
+	lang string
+}
+
+// 内置检查规则
+var lintRules = []LintRule{
+	{ID: "privileged-container", Severity: severityError, Description: "容器以特权模式运行", Check: checkPrivileged},
+	{ID: "host-path-volume", Severity: severityWarning, Description: "挂载了 hostPath 卷", Check: checkHostPath},
+	{ID: "run-as-root", Severity: severityWarning, Description: "容器可能以 root 用户运行", Check: checkRunAsRoot},
+	{ID: "missing-limits", Severity: severityWarning, Description: "容器未设置 CPU 或内存 limits", Check: checkMissingLimits},
+	{ID: "latest-tag", Severity: severityWarning, Description: "镜像使用 latest 标签或未指定标签", Check: checkLatestTag},
+	{ID: "missing-probes", Severity: severityInfo, Description: "容器未配置 readiness 或 liveness 探针", Check: checkMissingProbes},
+	{ID: "single-replica", Severity: severityInfo, Description: "Deployment / StatefulSet 只有一个副本", Check: checkSingleReplica},
+	{ID: "missing-pdb", Severity: severityInfo, Description: "多副本工作负载没有匹配的 PodDisruptionBudget", Check: checkMissingPDB},
+	{ID: "removed-api", Severity: severityError, Description: "使用了在目标版本 (-target-version) 中已移除的 API", Check: checkRemovedAPI},
+	{ID: "deprecated-api", Severity: severityWarning, Description: "使用了已弃用的 API 版本", Check: checkDeprecatedAPI},
+	{ID: "crd-schema", Severity: severityWarning, Description: "自定义资源不符合 CRD 的 OpenAPI schema", Check: checkCRDSchema},
+}
+
+// 不需要探针的批处理工作负载
+var batchKinds = map[string]bool{"Job": true, "CronJob": true}
+
+// 对容器逐个检查，返回带容器名的问题描述
+func eachContainer(ctx *lintContext, info ResourceInfo, includeInit bool, check func(spec, container map[string]interface{}) string) []string {
+	spec := podSpec(info)
+	var problems []string
+	for _, c := range podContainers(spec) {
+		if c.Init && !includeInit {
+			continue
+		}
+		if msg := check(spec, c.Spec); msg != "" {
+			problems = append(problems, tr(ctx.lang, "容器 %s: %s", c.Name, msg))
+		}
+	}
+	return problems
+}
+
+func checkPrivileged(ctx *lintContext, info ResourceInfo) []string {
+	return eachContainer(ctx, info, true, func(_, c map[string]interface{}) string {
+		if nestedValue(c, "securityContext", "privileged") == true {
+			return tr(ctx.lang, "securityContext.privileged 为 true")
+		}
+		return ""
+	})
+}
+
+func checkHostPath(ctx *lintContext, info ResourceInfo) []string {
+	var problems []string
+	for _, item := range nestedSlice(podSpec(info), "volumes") {
+		volume, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if hostPath := nestedMap(volume, "hostPath"); hostPath != nil {
+			problems = append(problems, tr(ctx.lang, "卷 %s 挂载了宿主机路径 %s", nestedString(volume, "name"), nestedString(hostPath, "path")))
+		}
+	}
+	return problems
+}
+
+func checkRunAsRoot(ctx *lintContext, info ResourceInfo) []string {
+	return eachContainer(ctx, info, true, func(spec, c map[string]interface{}) string {
+		// 容器级 securityContext 优先于 Pod 级
+		runAsUser := nestedValue(c, "securityContext", "runAsUser")
+		if runAsUser == nil {
+			runAsUser = nestedValue(spec, "securityContext", "runAsUser")
+		}
+		runAsNonRoot := nestedValue(c, "securityContext", "runAsNonRoot")
+		if runAsNonRoot == nil {
+			runAsNonRoot = nestedValue(spec, "securityContext", "runAsNonRoot")
+		}
+		if runAsUser != nil && stringValue(runAsUser) == "0" {
+			return tr(ctx.lang, "runAsUser 为 0")
+		}
+		if runAsUser == nil && runAsNonRoot != true {
+			return tr(ctx.lang, "未设置 runAsNonRoot 或非 0 的 runAsUser")
+		}
+		return ""
+	})
+}
+
+func checkMissingLimits(ctx *lintContext, info ResourceInfo) []string {
+	return eachContainer(ctx, info, false, func(_, c map[string]interface{}) string {
+		limits := resourceAmounts(nestedMap(c, "resources", "limits"))
+		var missing []string
+		for _, name := range []string{"cpu", "memory"} {
+			if limits[name] == 0 {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			return tr(ctx.lang, "未设置 %s limits", strings.Join(missing, tr(ctx.lang, "、")))
+		}
+		return ""
+	})
+}
+
+func checkLatestTag(ctx *lintContext, info ResourceInfo) []string {
+	return eachContainer(ctx, info, true, func(_, c map[string]interface{}) string {
+		image := nestedString(c, "image")
+		ref := parseImageReference(image)
+		if image != "" && ref.Digest == "" && (ref.Tag == "" || ref.Tag == "latest") {
+			return tr(ctx.lang, "镜像 %s 使用 latest 标签", image)
+		}
+		return ""
+	})
+}
+
+func checkMissingProbes(ctx *lintContext, info ResourceInfo) []string {
+	if batchKinds[info.Kind] {
+		return nil
+	}
+	return eachContainer(ctx, info, false, func(_, c map[string]interface{}) string {
+		var missing []string
+		for _, probe := range []string{"readinessProbe", "livenessProbe"} {
+			if nestedMap(c, probe) == nil {
+				missing = append(missing, probe)
+			}
+		}
+		if len(missing) > 0 {
+			return tr(ctx.lang, "缺少 %s", strings.Join(missing, tr(ctx.lang, "、")))
+		}
+		return ""
+	})
+}
+
+func checkSingleReplica(ctx *lintContext, info ResourceInfo) []string {
+	if info.Kind != "Deployment" && info.Kind != "StatefulSet" {
+		return nil
+	}
+	if workloadReplicas(info) == 1 {
+		return []string{tr(ctx.lang, "只有 1 个副本，节点维护或 Pod 重建时服务会中断")}
+	}
+	return nil
+}
+
+func checkMissingPDB(ctx *lintContext, info ResourceInfo) []string {
+	if info.Kind != "Deployment" && info.Kind != "StatefulSet" || workloadReplicas(info) < 2 {
+		return nil
+	}
+	podLabels := stringMap(nestedMap(info.Parsed, "spec", "template", "metadata", "labels"))
+	for _, pdb := range ctx.pdbs {
+		if pdb.Namespace != info.Namespace {
+			continue
+		}
+		// 选择器无效的 PDB 不会生效，不算作匹配
+		selector := nestedMap(pdb.Parsed, "spec", "selector")
+		if selector == nil {
+			continue
+		}
+		if s, err := labelSelectorFromObject(selector); err == nil && s.Matches(podLabels) {
+			return nil
+		}
+	}
+	return []string{tr(ctx.lang, "%d 个副本，但已加载的资源中没有匹配的 PodDisruptionBudget", workloadReplicas(info))}
+}
+
+// 对所有资源执行内置规则，结果按严重程度和资源顺序排序，问题描述使用 lang 语言
+func runLint(infos []ResourceInfo, lang string) []Finding {
+	ctx := &lintContext{lang: lang}
+	for _, info := range infos {
+		if info.Kind == "PodDisruptionBudget" {
+			ctx.pdbs = append(ctx.pdbs, info)
+		}
+	}
+
+	findings := []Finding{}
+	for i, info := range infos {
+		for _, rule := range lintRules {
+			for _, msg := range rule.Check(ctx, info) {
+				findings = append(findings, Finding{
+					Rule:      rule.ID,
+					Severity:  rule.Severity,
+					Message:   msg,
+					Index:     i,
+					Kind:      info.Kind,
+					Namespace: info.Namespace,
+					Name:      info.Name,
+				})
+			}
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return severityRank[findings[i].Severity] < severityRank[findings[j].Severity]
+	})
+	return findings
+}
+
+// 页面展示用的检查结果汇总
+type LintReport struct {
+	Findings []Finding
+	Rules    []LintRule
+	Counts   map[string]int
+	ByIndex  map[int][]Finding // 每个资源的检查结果，用于卡片徽章
+}
+
+func buildLintReport(infos []ResourceInfo, lang string) LintReport {
+	report := LintReport{
+		Findings: runLint(infos, lang),
+		Rules:    lintRules,
+		Counts:   make(map[string]int),
+		ByIndex:  make(map[int][]Finding),
+	}
+	for _, f := range report.Findings {
+		report.Counts[f.Severity]++
+		report.ByIndex[f.Index] = append(report.ByIndex[f.Index], f)
+	}
+	return report
+}
+
+// 资源卡片上显示的最高严重程度
+func (r LintReport) WorstSeverity(index int) string {
+	worst := ""
+	for _, f := range r.ByIndex[index] {
+		if worst == "" || severityRank[f.Severity] < severityRank[worst] {
+			worst = f.Severity
+		}
+	}
+	return worst
+}
+
+// 以文本形式输出检查结果，返回达到 failOn 严重程度的结果数
+func printLintFindings(w io.Writer, findings []Finding, failOn, lang string) int {
+	failures := 0
+	for _, f := range findings {
+		resource := f.Kind + "/" + f.Name
+		if f.Namespace != "" {
+			resource = f.Namespace + "/" + resource
+		}
+		fmt.Fprintf(w, "%-7s %-20s %s: %s\n", strings.ToUpper(f.Severity), f.Rule, resource, f.Message)
+		if severityRank[f.Severity] <= severityRank[failOn] {
+			failures++
+		}
+	}
+	counts := make(map[string]int)
+	for _, f := range findings {
+		counts[f.Severity]++
+	}
+	fmt.Fprint(w, "\n"+tr(lang, "共 %d 条: %d error, %d warning, %d info", len(findings), counts[severityError], counts[severityWarning], counts[severityInfo])+"\n")
+	return failures
+}
+
+// GET /api/v1/lint?severity=error|warning|info，可与 /api/v1/resources 的过滤参数组合使用
+func apiLintHandler(pageFor func(*http.Request) PageData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page := pageFor(r)
+		indices, err := filterResources(page.Resources, r)
+		if err != nil {
+			writeAPIError(w, r, http.StatusBadRequest, "%v", err)
+			return
+		}
+		severity := r.URL.Query().Get("severity")
+		if _, ok := severityRank[severity]; severity != "" && !ok {
+			writeAPIError(w, r, http.StatusBadRequest, "无效的严重程度: %s（可选 error、warning、info）", severity)
+			return
+		}
+
+		selected := make(map[int]bool, len(indices))
+		for _, i := range indices {
+			selected[i] = true
+		}
+		findings := []Finding{}
+		for _, f := range page.Lint.Findings {
+			if selected[f.Index] && (severity == "" || severityRank[f.Severity] <= severityRank[severity]) {
+				findings = append(findings, f)
+			}
+		}
+		writeJSON(w, http.StatusOK, findings)
+	}
+}
@@ -28,6 +28,9 @@ type K8sResource struct {
 	Rules      interface{}            `yaml:"rules,omitempty" json:"rules,omitempty"`
 	Subjects   interface{}            `yaml:"subjects,omitempty" json:"subjects,omitempty"`
 	RoleRef    interface{}            `yaml:"roleRef,omitempty" json:"roleRef,omitempty"`
+
+	// 其他顶层字段（如 Secret 的 type、StorageClass 的 provisioner）
+	Extra map[string]interface{} `yaml:",inline" json:"-"`
 }
 
 type K8sList struct {
@@ -45,834 +48,46 @@ type ResourceInfo struct {
 	Status     string                 `json:"status"`
 	YAML       string                 `json:"yaml"`
 	Parsed     map[string]interface{} `json:"parsed"`
-}
 
-// HTML 模板（内嵌）
-const htmlTemplate = `
-<!DOCTYPE html>
-<html lang="zh-CN">
-<head>
-  <meta charset="UTF-8">
-  <meta name="viewport" content="width=device-width, initial-scale=1.0">
-  <title>kubectl html - {{ .Command }}</title>
-  <style>
-    * { box-sizing: border-box; }
-    body { 
-      font-family: 'Segoe UI', Tahoma, Geneva, Verdana, sans-serif; 
-      margin: 0; padding: 20px; 
-      background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
-      min-height: 100vh;
-    }
-    .container { 
-      max-width: 1400px; margin: 0 auto; 
-      background: white; border-radius: 12px; 
-      box-shadow: 0 10px 30px rgba(0,0,0,0.2);
-      overflow: hidden;
-    }
-    .header { 
-      background: linear-gradient(135deg, #2c3e50, #34495e);
-      color: white; padding: 30px; 
-      border-bottom: 3px solid #3498db;
-    }
-    .header h1 { margin: 0 0 15px 0; font-size: 2.5em; }
-    .header .meta { display: flex; gap: 30px; flex-wrap: wrap; }
-    .header .meta-item { display: flex; flex-direction: column; }
-    .header .meta-label { font-size: 0.9em; opacity: 0.8; margin-bottom: 5px; }
-    .header .meta-value { font-size: 1.1em; font-weight: bold; }
-    
-    .content { padding: 30px; }
-    .resource-grid { 
-      display: grid; 
-      grid-template-columns: repeat(auto-fit, minmax(400px, 1fr)); 
-      gap: 20px; margin-bottom: 30px;
-    }
-    .resource-card { 
-      border: 1px solid #e1e8ed; 
-      border-radius: 8px; 
-      overflow: hidden;
-      transition: transform 0.2s, box-shadow 0.2s;
-      cursor: pointer;
-    }
-    .resource-card:hover { 
-      transform: translateY(-2px); 
-      box-shadow: 0 5px 15px rgba(0,0,0,0.1);
-    }
-    .resource-header { 
-      background: #f8f9fa; 
-      padding: 15px; 
-      border-bottom: 1px solid #e1e8ed;
-    }
-    .resource-title { 
-      font-weight: bold; 
-      font-size: 1.1em; 
-      color: #2c3e50;
-      margin-bottom: 5px;
-    }
-    .resource-meta { 
-      font-size: 0.9em; 
-      color: #6c757d;
-      display: flex; gap: 15px; flex-wrap: wrap;
-    }
-    
-    .summary-stats { 
-      display: grid; 
-      grid-template-columns: repeat(auto-fit, minmax(200px, 1fr)); 
-      gap: 20px; margin-bottom: 30px;
-    }
-    .stat-card { 
-      background: linear-gradient(135deg, #3498db, #2980b9);
-      color: white; padding: 20px; 
-      border-radius: 8px; text-align: center;
-    }
-    .stat-number { font-size: 2em; font-weight: bold; margin-bottom: 5px; }
-    .stat-label { font-size: 0.9em; opacity: 0.9; }
-    
-    .status-badge { 
-      padding: 4px 8px; 
-      border-radius: 12px; 
-      font-size: 0.8em; 
-      font-weight: bold;
-    }
-    .status-running { background: #d4edda; color: #155724; }
-    .status-pending { background: #fff3cd; color: #856404; }
-    .status-failed { background: #f8d7da; color: #721c24; }
-    .status-unknown { background: #e2e3e5; color: #383d41; }
-    
-    .refresh-btn { 
-      position: fixed; 
-      bottom: 30px; right: 30px; 
-      background: #3498db; color: white; 
-      border: none; padding: 15px; 
-      border-radius: 50%; 
-      cursor: pointer; 
-      box-shadow: 0 5px 15px rgba(0,0,0,0.2);
-      transition: all 0.3s;
-    }
-    .refresh-btn:hover { 
-      background: #2980b9; 
-      transform: scale(1.1);
-    }
-    
-    /* 模态框样式 */
-    .modal {
-      display: none;
-      position: fixed;
-      z-index: 1000;
-      left: 0;
-      top: 0;
-      width: 100%;
-      height: 100%;
-      background-color: rgba(0,0,0,0.5);
-      animation: fadeIn 0.3s;
-      overflow-y: auto;
-    }
-    
-    /* 阻止背景滚动 */
-    body.modal-open {
-      overflow: hidden;
-    }
-    
-    /* 全屏模态框 */
-    .modal.fullscreen .modal-content {
-      width: 100vw;
-      height: 100vh;
-      max-width: none;
-      max-height: none;
-      margin: 0;
-      border-radius: 0;
-      animation: expandToFullscreen 0.3s ease-out;
-    }
-    
-    .modal.fullscreen .modal-body {
-      max-height: none;
-      height: calc(100vh - 80px);
-    }
-    
-    @keyframes expandToFullscreen {
-      from { 
-        width: 90%;
-        height: 80vh;
-        margin: 5% auto;
-        border-radius: 12px;
-      }
-      to { 
-        width: 100vw;
-        height: 100vh;
-        margin: 0;
-        border-radius: 0;
-      }
-    }
-    
-    .modal-content {
-      background-color: white;
-      margin: 5% auto;
-      padding: 0;
-      border-radius: 12px;
-      width: 90%;
-      max-width: 1000px;
-      max-height: 80vh;
-      overflow: hidden;
-      box-shadow: 0 20px 60px rgba(0,0,0,0.3);
-      animation: slideIn 0.3s;
-      display: flex;
-      flex-direction: column;
-    }
-    
-    .modal-header {
-      background: linear-gradient(135deg, #2c3e50, #34495e);
-      color: white;
-      padding: 20px 30px;
-      display: flex;
-      justify-content: space-between;
-      align-items: center;
-    }
-    
-    .modal-title {
-      font-size: 1.5em;
-      font-weight: bold;
-      margin: 0;
-    }
-    
-    .modal-subtitle {
-      font-size: 0.9em;
-      opacity: 0.8;
-      margin: 5px 0 0 0;
-    }
-    
-    .modal-controls {
-      display: flex;
-      align-items: center;
-      gap: 15px;
-    }
-    
-    .modal-control-btn {
-      color: white;
-      font-size: 20px;
-      cursor: pointer;
-      transition: opacity 0.3s;
-      padding: 5px;
-      border-radius: 4px;
-      background: rgba(255,255,255,0.1);
-    }
-    
-    .modal-control-btn:hover {
-      opacity: 0.7;
-      background: rgba(255,255,255,0.2);
-    }
-    
-    .close {
-      color: white;
-      font-size: 28px;
-      font-weight: bold;
-      cursor: pointer;
-      transition: opacity 0.3s;
-    }
-    
-    .close:hover {
-      opacity: 0.7;
-    }
-    
-    .modal-body {
-      padding: 30px;
-      flex: 1;
-      overflow-y: auto;
-      min-height: 0;
-    }
-    
-    .yaml-content { 
-      background: #f8f9fa; 
-      padding: 20px; 
-      border-radius: 8px; 
-      font-family: 'Consolas', 'Monaco', 'Courier New', monospace;
-      font-size: 0.9em;
-      line-height: 1.4;
-      overflow-x: auto;
-      white-space: pre;
-      border: 1px solid #e1e8ed;
-      margin: 0;
-    }
-    
-    /* 结构化资源显示 */
-    .resource-section {
-      margin-bottom: 25px;
-      border: 1px solid #e1e8ed;
-      border-radius: 8px;
-      overflow: hidden;
-    }
-    
-    .section-header {
-      background: linear-gradient(135deg, #f8f9fa, #e9ecef);
-      padding: 12px 20px;
-      border-bottom: 1px solid #e1e8ed;
-      font-weight: bold;
-      color: #2c3e50;
-      display: flex;
-      justify-content: space-between;
-      align-items: center;
-      cursor: pointer;
-      transition: background 0.3s;
-    }
-    
-    .section-header:hover {
-      background: linear-gradient(135deg, #e9ecef, #dee2e6);
-    }
-    
-    .section-header .toggle-icon {
-      font-size: 0.8em;
-      transition: transform 0.3s;
-    }
-    
-    .section-header.collapsed .toggle-icon {
-      transform: rotate(-90deg);
-    }
-    
-    .section-content {
-      padding: 20px;
-      background: white;
-      max-height: 400px;
-      overflow-y: auto;
-    }
-    
-    .section-content.collapsed {
-      display: none;
-    }
-    
-    .key-value-grid {
-      display: grid;
-      grid-template-columns: 200px 1fr;
-      gap: 15px 20px;
-      align-items: start;
-    }
-    
-    .key-label {
-      font-weight: 600;
-      color: #495057;
-      padding: 8px 0;
-      border-bottom: 1px solid #f1f3f4;
-    }
-    
-    .value-content {
-      padding: 8px 0;
-      border-bottom: 1px solid #f1f3f4;
-      word-break: break-word;
-    }
-    
-    .value-object {
-      background: #f8f9fa;
-      padding: 15px;
-      border-radius: 6px;
-      border-left: 4px solid #3498db;
-    }
-    
-    .value-array {
-      background: #fff3cd;
-      padding: 15px;
-      border-radius: 6px;
-      border-left: 4px solid #ffc107;
-    }
-    
-    .array-item {
-      padding: 8px 12px;
-      margin: 5px 0;
-      background: white;
-      border-radius: 4px;
-      border: 1px solid #e9ecef;
-    }
-    
-    .nested-object {
-      margin-left: 20px;
-      padding-left: 15px;
-      border-left: 2px solid #e9ecef;
-    }
-    
-    .value-string {
-      color: #28a745;
-      font-family: 'Consolas', monospace;
-    }
-    
-    .value-number {
-      color: #007bff;
-      font-family: 'Consolas', monospace;
-    }
-    
-    .value-boolean {
-      color: #dc3545;
-      font-family: 'Consolas', monospace;
-      font-weight: bold;
-    }
-    
-    .value-null {
-      color: #6c757d;
-      font-style: italic;
-    }
-    
-    .tab-buttons {
-      display: flex;
-      background: #f8f9fa;
-      border-bottom: 1px solid #dee2e6;
-      margin: -30px -30px 20px -30px;
-    }
-    
-    .tab-button {
-      padding: 12px 20px;
-      border: none;
-      background: none;
-      cursor: pointer;
-      font-size: 0.9em;
-      color: #6c757d;
-      transition: all 0.3s;
-      border-bottom: 3px solid transparent;
-    }
-    
-    .tab-button:hover {
-      background: #e9ecef;
-      color: #495057;
-    }
-    
-    .tab-button.active {
-      background: white;
-      color: #2c3e50;
-      border-bottom-color: #3498db;
-      font-weight: bold;
-    }
-    
-    .tab-content {
-      display: none;
-    }
-    
-    .tab-content.active {
-      display: block;
-    }
-    
-    @keyframes fadeIn {
-      from { opacity: 0; }
-      to { opacity: 1; }
-    }
-    
-    @keyframes slideIn {
-      from { transform: translateY(-50px); opacity: 0; }
-      to { transform: translateY(0); opacity: 1; }
-    }
-    
-    @media (max-width: 768px) {
-      .resource-grid { grid-template-columns: 1fr; }
-      .header .meta { flex-direction: column; gap: 15px; }
-      .modal-content { 
-        width: 95%; 
-        margin: 5% auto;
-        max-height: 85vh;
-      }
-      .modal-header { padding: 15px 20px; }
-      .modal-body { padding: 15px; }
-      .key-value-grid { 
-        grid-template-columns: 1fr; 
-        gap: 10px;
-      }
-      .key-label {
-        background: #f8f9fa;
-        padding: 8px 12px;
-        border-radius: 4px;
-        margin-bottom: 5px;
-      }
-      .tab-buttons {
-        margin: -15px -15px 15px -15px;
-      }
-      .tab-button {
-        padding: 10px 15px;
-        font-size: 0.8em;
-      }
-      .modal-controls {
-        gap: 10px;
-      }
-      .modal-control-btn {
-        font-size: 18px;
-        padding: 3px;
-      }
-      .close {
-        font-size: 24px;
-      }
-      
-      /* 移动端全屏优化 */
-      .modal.fullscreen .modal-content {
-        width: 100vw;
-        height: 100vh;
-        margin: 0;
-        border-radius: 0;
-      }
-      
-      .modal.fullscreen .modal-body {
-        height: calc(100vh - 70px);
-        padding: 10px;
-      }
-      
-      .modal.fullscreen .modal-header {
-        padding: 10px 15px;
-      }
-    }
-  </style>
-</head>
-<body>
-  <div class="container">
-    <div class="header">
-      <h1>🚀 Kubernetes 资源查看器</h1>
-      <div class="meta">
-        <div class="meta-item">
-          <div class="meta-label">执行命令</div>
-          <div class="meta-value">kubectl {{ .Command }}</div>
-        </div>
-        <div class="meta-item">
-          <div class="meta-label">生成时间</div>
-          <div class="meta-value">{{ .Timestamp }}</div>
-        </div>
-        <div class="meta-item">
-          <div class="meta-label">资源总数</div>
-          <div class="meta-value">{{ .TotalResources }}</div>
-        </div>
-        <div class="meta-item">
-          <div class="meta-label">命名空间</div>
-          <div class="meta-value">{{ .NamespaceCount }}</div>
-        </div>
-      </div>
-    </div>
-    
-    <div class="content">
-      <div class="summary-stats">
-        {{ range .KindStats }}
-        <div class="stat-card">
-          <div class="stat-number">{{ .Count }}</div>
-          <div class="stat-label">{{ .Kind }}</div>
-        </div>
-        {{ end }}
-      </div>
-      
-      {{ if .Resources }}
-      <h3>📋 资源列表 (点击查看详情)</h3>
-      <div class="resource-grid">
-        {{ range $index, $resource := .Resources }}
-        <div class="resource-card" onclick="showResourceModal({{ $index }})">
-          <div class="resource-header">
-            <div class="resource-title">{{ .Name }}</div>
-            <div class="resource-meta">
-              <span>🏷️ {{ .Kind }}</span>
-              {{ if .Namespace }}<span>📁 {{ .Namespace }}</span>{{ end }}
-              <span>⏰ {{ .Age }}</span>
-              <span class="status-badge status-{{ .Status }}">{{ .Status }}</span>
-            </div>
-          </div>
-        </div>
-        {{ end }}
-      </div>
-      {{ end }}
-    </div>
-  </div>
-  
-  <!-- 模态框 -->
-  <div id="resourceModal" class="modal">
-    <div class="modal-content">
-      <div class="modal-header">
-        <div>
-          <div class="modal-title" id="modalTitle">资源详情</div>
-          <div class="modal-subtitle" id="modalSubtitle">YAML 配置</div>
-        </div>
-        <div class="modal-controls">
-          <span class="modal-control-btn" onclick="toggleFullscreen()" id="fullscreenBtn" title="放大到全屏 (F11)">🔍</span>
-          <span class="close" onclick="closeModal()" title="关闭">&times;</span>
-        </div>
-      </div>
-      <div class="modal-body">
-        <div class="tab-buttons">
-          <button class="tab-button active" onclick="switchTab('structured')">📋 结构化视图</button>
-          <button class="tab-button" onclick="switchTab('yaml')">📄 YAML 源码</button>
-        </div>
-        
-        <div id="structuredTab" class="tab-content active">
-          <div id="structuredContent">加载中...</div>
-        </div>
-        
-        <div id="yamlTab" class="tab-content">
-          <pre class="yaml-content" id="modalYaml">加载中...</pre>
-        </div>
-      </div>
-    </div>
-  </div>
-  
-  <button class="refresh-btn" onclick="location.reload()" title="刷新页面">🔄</button>
-  
-  <script>
-    // 资源数据
-    const resources = {{ .ResourcesJSON }};
-    
-    function renderValue(value, key = '') {
-      if (value === null || value === undefined) {
-        return '<span class="value-null">null</span>';
-      }
-      
-      if (typeof value === 'string') {
-        return '<span class="value-string">"' + escapeHtml(value) + '"</span>';
-      }
-      
-      if (typeof value === 'number') {
-        return '<span class="value-number">' + value + '</span>';
-      }
-      
-      if (typeof value === 'boolean') {
-        return '<span class="value-boolean">' + value + '</span>';
-      }
-      
-      if (Array.isArray(value)) {
-        if (value.length === 0) {
-          return '<span class="value-null">[]</span>';
-        }
-        
-        let html = '<div class="value-array">';
-        html += '<strong>📋 数组 (' + value.length + ' 项)</strong>';
-        value.forEach((item, index) => {
-          html += '<div class="array-item">';
-          if (typeof item === 'object' && item !== null) {
-            html += '<strong>🔸 [' + index + ']</strong><br>';
-            html += renderValue(item);
-          } else {
-            html += '<strong>🔸 [' + index + ']</strong> ' + renderValue(item);
-          }
-          html += '</div>';
-        });
-        html += '</div>';
-        return html;
-      }
-      
-      if (typeof value === 'object') {
-        const keys = Object.keys(value);
-        if (keys.length === 0) {
-          return '<span class="value-null">{}</span>';
-        }
-        
-        let html = '<div class="value-object">';
-        html += '<strong>📦 对象 (' + keys.length + ' 个字段)</strong>';
-        html += '<div class="key-value-grid" style="margin-top: 10px;">';
-        
-        keys.forEach(k => {
-          html += '<div class="key-label">🔑 ' + escapeHtml(k) + '</div>';
-          html += '<div class="value-content">' + renderValue(value[k], k) + '</div>';
-        });
-        
-        html += '</div></div>';
-        return html;
-      }
-      
-      return '<span class="value-string">' + escapeHtml(String(value)) + '</span>';
-    }
-    
-    function escapeHtml(text) {
-      const div = document.createElement('div');
-      div.textContent = text;
-      return div.innerHTML;
-    }
-    
-    function renderStructuredResource(parsedResource) {
-      try {
-        if (!parsedResource || typeof parsedResource !== 'object') {
-          return '<p>无法解析资源结构</p>';
-        }
-        
-        // 生成结构化 HTML
-        let html = '';
-        
-        // 主要部分
-        const sections = [
-          { key: 'apiVersion', title: 'API 版本', icon: '🔖' },
-          { key: 'kind', title: '资源类型', icon: '📦' },
-          { key: 'metadata', title: '元数据', icon: '📋' },
-          { key: 'spec', title: '规格配置', icon: '⚙️' },
-          { key: 'status', title: '状态信息', icon: '📊' },
-          { key: 'data', title: '数据', icon: '💾' },
-          { key: 'stringData', title: '字符串数据', icon: '📝' },
-          { key: 'rules', title: '规则', icon: '📜' },
-          { key: 'subjects', title: '主体', icon: '👥' },
-          { key: 'roleRef', title: '角色引用', icon: '🔗' }
-        ];
-        
-        sections.forEach(section => {
-          if (parsedResource[section.key] !== undefined) {
-            html += '<div class="resource-section">';
-            html += '<div class="section-header" onclick="toggleSection(this)">';
-            html += '<span>' + section.icon + ' ' + section.title + '</span>';
-            html += '<span class="toggle-icon">▼</span>';
-            html += '</div>';
-            html += '<div class="section-content">';
-            html += renderValue(parsedResource[section.key]);
-            html += '</div>';
-            html += '</div>';
-          }
-        });
-        
-        // 其他字段
-        const otherKeys = Object.keys(parsedResource).filter(key => 
-          !sections.some(section => section.key === key)
-        );
-        
-        if (otherKeys.length > 0) {
-          html += '<div class="resource-section">';
-          html += '<div class="section-header" onclick="toggleSection(this)">';
-          html += '<span>🔧 其他字段</span>';
-          html += '<span class="toggle-icon">▼</span>';
-          html += '</div>';
-          html += '<div class="section-content">';
-          html += '<div class="key-value-grid">';
-          otherKeys.forEach(key => {
-            html += '<div class="key-label">' + escapeHtml(key) + '</div>';
-            html += '<div class="value-content">' + renderValue(parsedResource[key]) + '</div>';
-          });
-          html += '</div>';
-          html += '</div>';
-          html += '</div>';
-        }
-        
-        return html || '<p>无法解析资源结构</p>';
-        
-      } catch (error) {
-        return '<p>解析错误: ' + escapeHtml(error.message) + '</p>';
-      }
-    }
-    
-    function toggleSection(header) {
-      const content = header.nextElementSibling;
-      const icon = header.querySelector('.toggle-icon');
-      
-      if (content.classList.contains('collapsed')) {
-        content.classList.remove('collapsed');
-        header.classList.remove('collapsed');
-        icon.textContent = '▼';
-      } else {
-        content.classList.add('collapsed');
-        header.classList.add('collapsed');
-        icon.textContent = '▶';
-      }
-    }
-    
-    function switchTab(tabName) {
-      // 隐藏所有标签页
-      document.querySelectorAll('.tab-content').forEach(tab => {
-        tab.classList.remove('active');
-      });
-      
-      // 移除所有按钮的活动状态
-      document.querySelectorAll('.tab-button').forEach(btn => {
-        btn.classList.remove('active');
-      });
-      
-      // 显示选中的标签页
-      document.getElementById(tabName + 'Tab').classList.add('active');
-      event.target.classList.add('active');
-    }
-    
-    function showResourceModal(index) {
-      const resource = resources[index];
-      const modal = document.getElementById('resourceModal');
-      const title = document.getElementById('modalTitle');
-      const subtitle = document.getElementById('modalSubtitle');
-      const yaml = document.getElementById('modalYaml');
-      const structured = document.getElementById('structuredContent');
-      
-      title.textContent = resource.name || 'Unknown Resource';
-      subtitle.textContent = resource.kind + (resource.namespace ? ' (' + resource.namespace + ')' : '') + ' - ' + resource.apiVersion;
-      yaml.textContent = resource.yaml;
-      
-      // 生成结构化视图
-      structured.innerHTML = renderStructuredResource(resource.parsed);
-      
-      // 重置到结构化视图
-      document.querySelectorAll('.tab-content').forEach(tab => tab.classList.remove('active'));
-      document.querySelectorAll('.tab-button').forEach(btn => btn.classList.remove('active'));
-      document.getElementById('structuredTab').classList.add('active');
-      document.querySelector('.tab-button').classList.add('active');
-      
-      // 阻止背景滚动
-      document.body.classList.add('modal-open');
-      modal.style.display = 'block';
-    }
-    
-    function closeModal() {
-      const modal = document.getElementById('resourceModal');
-      modal.style.display = 'none';
-      modal.classList.remove('fullscreen');
-      // 恢复背景滚动
-      document.body.classList.remove('modal-open');
-      // 重置全屏按钮
-      const fullscreenBtn = document.getElementById('fullscreenBtn');
-      fullscreenBtn.textContent = '🔍';
-      fullscreenBtn.title = '放大到全屏 (F11)';
-    }
-    
-    function toggleFullscreen() {
-      const modal = document.getElementById('resourceModal');
-      const fullscreenBtn = document.getElementById('fullscreenBtn');
-      
-      if (modal.classList.contains('fullscreen')) {
-        // 退出全屏
-        modal.classList.remove('fullscreen');
-        fullscreenBtn.textContent = '🔍';
-        fullscreenBtn.title = '放大到全屏 (F11)';
-      } else {
-        // 进入全屏
-        modal.classList.add('fullscreen');
-        fullscreenBtn.textContent = '🔎';
-        fullscreenBtn.title = '退出全屏 (F11)';
-      }
-    }
-    
-    // 点击模态框外部关闭
-    window.onclick = function(event) {
-      const modal = document.getElementById('resourceModal');
-      if (event.target === modal) {
-        closeModal();
-      }
-    }
-    
-    // 键盘快捷键
-    document.addEventListener('keydown', function(event) {
-      const modal = document.getElementById('resourceModal');
-      
-      if (event.key === 'Escape') {
-        closeModal();
-      } else if (event.key === 'F11' && modal.style.display === 'block') {
-        event.preventDefault();
-        toggleFullscreen();
-      }
-    });
-    
-    // 阻止模态框内容滚动事件冒泡
-    document.addEventListener('DOMContentLoaded', function() {
-      const modalContent = document.querySelector('.modal-content');
-      if (modalContent) {
-        modalContent.addEventListener('wheel', function(e) {
-          e.stopPropagation();
-        });
-        
-        modalContent.addEventListener('touchmove', function(e) {
-          e.stopPropagation();
-        });
-      }
-    });
-  </script>
-</body>
-</html>
-`
+	ManagedFields []ManagedFieldsEntry `json:"managedFields,omitempty"`
+	Certificates  []CertificateSource  `json:"certificates,omitempty"`
+	Containers    []ContainerDetail    `json:"containers,omitempty"`
+	Uses          []ResourceRef        `json:"uses,omitempty"`
+	UsedBy        []ResourceRef        `json:"usedBy,omitempty"`
+	GitOps        *GitOpsApp           `json:"gitops,omitempty"`
+	SchemaIssues  []SchemaIssue        `json:"schemaIssues,omitempty"`
+}
 
 type KindStat struct {
 	Kind  string
 	Count int
 }
 
-
-
 type PageData struct {
 	Command        string
 	Timestamp      string
 	TotalResources int
 	NamespaceCount int
+	CleanView      bool
 	Resources      []ResourceInfo
 	KindStats      []KindStat
-	ResourcesJSON  template.JS
+	LabelIndex     LabelIndex        `json:"-"`
+	Images         ImageInventory    `json:"-"`
+	Capacity       CapacityReport    `json:"-"`
+	Lint           LintReport        `json:"-"`
+	Deprecations   DeprecationReport `json:"-"`
+	PodSecurity    PSSReport         `json:"-"`
+	Certificates   CertificateReport `json:"-"`
+	References     ReferenceReport   `json:"-"`
+	Storage        StorageReport     `json:"-"`
+	Routes         RouteMap          `json:"-"`
+	Helm           HelmReport        `json:"-"`
+	GitOps         GitOpsReport      `json:"-"`
+	Nodes          NodeDashboard     `json:"-"`
+	ResourcesJSON  template.JS       `json:"-"`
+	Lang           string            `json:"-"` // 页面语言
+	Languages      []Language        `json:"-"`
+	Messages       template.JS       `json:"-"` // 前端 t() 使用的消息目录
 }
 
 // 解析资源状态
@@ -886,6 +101,11 @@ func getResourceStatus(resource K8sResource) string {
 		return "unknown"
 	}
 
+	// GitOps 对象使用各自的同步与健康状态
+	if status, ok := gitOpsStatus(resource.APIVersion, resource.Kind, statusMap, resource.Spec); ok {
+		return status
+	}
+
 	// 检查不同类型资源的状态
 	switch resource.Kind {
 	case "Pod":
@@ -916,6 +136,32 @@ func getResourceStatus(resource K8sResource) string {
 			}
 		}
 		return "pending"
+	case "Node":
+		// Ready 条件为 True 时视为运行中，False 表示节点异常
+		if conditions, ok := statusMap["conditions"].([]interface{}); ok {
+			for _, cond := range conditions {
+				if condMap, ok := cond.(map[string]interface{}); ok && condMap["type"] == "Ready" {
+					switch condMap["status"] {
+					case "True":
+						return "running"
+					case "False":
+						return "failed"
+					}
+				}
+			}
+		}
+		return "unknown"
+	case "PersistentVolumeClaim", "PersistentVolume":
+		// Bound 为正常，Available 的 PV 等待绑定，Lost / Released / Failed 视为异常
+		switch statusMap["phase"] {
+		case "Bound":
+			return "running"
+		case "Pending", "Available":
+			return "pending"
+		case "Lost", "Released", "Failed":
+			return "failed"
+		}
+		return "unknown"
 	case "Service":
 		return "running"
 	case "ConfigMap", "Secret":
@@ -986,9 +232,9 @@ func parseKubernetesYAML(yamlData string) ([]K8sResource, error) {
 	}
 
 	// 如果不是 List，则分割多文档 YAML
-	docs := strings.Split(yamlData, "---")
+	docs := splitYAMLDocuments(yamlData)
 	log.Printf("📄 Split into %d documents", len(docs))
-	
+
 	for i, doc := range docs {
 		doc = strings.TrimSpace(doc)
 		if doc == "" {
@@ -1008,15 +254,35 @@ func parseKubernetesYAML(yamlData string) ([]K8sResource, error) {
 	return resources, nil
 }
 
-// 生成资源信息
-func generateResourceInfo(resources []K8sResource) []ResourceInfo {
+// 按独占一行的 --- 分割多文档 YAML，证书等内容中的 ----- 不会被误判为分隔符
+func splitYAMLDocuments(data string) []string {
+	var docs []string
+	var current strings.Builder
+	for _, line := range strings.SplitAfter(data, "\n") {
+		if trimmed := strings.TrimRight(line, " \t\r\n"); trimmed == "---" || strings.HasPrefix(trimmed, "--- ") {
+			docs = append(docs, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteString(line)
+	}
+	return append(docs, current.String())
+}
+
+// 生成资源信息，clean 为 true 时去除服务端填充的噪声元数据，lang 为生成的提示信息的语言
+func generateResourceInfo(resources []K8sResource, clean bool, lang string) []ResourceInfo {
 	var infos []ResourceInfo
 
 	for _, resource := range resources {
 		info := ResourceInfo{
-			Kind:       resource.Kind,
-			APIVersion: resource.APIVersion,
-			Status:     getResourceStatus(resource),
+			Kind:          resource.Kind,
+			APIVersion:    resource.APIVersion,
+			Status:        getResourceStatus(resource),
+			ManagedFields: parseManagedFields(resource.Metadata),
+		}
+
+		if clean {
+			resource = cleanResource(resource)
 		}
 
 		// 提取名称和命名空间
@@ -1037,7 +303,7 @@ func generateResourceInfo(resources []K8sResource) []ResourceInfo {
 		if err == nil {
 			info.YAML = string(yamlBytes)
 		} else {
-			info.YAML = "# YAML 生成失败: " + err.Error()
+			info.YAML = tr(lang, "# YAML 生成失败: %v", err)
 		}
 
 		// 解析为 map 供前端使用
@@ -1046,9 +312,13 @@ func generateResourceInfo(resources []K8sResource) []ResourceInfo {
 			info.Parsed = parsed
 		} else {
 			info.Parsed = map[string]interface{}{
-				"error": "解析失败: " + err.Error(),
+				"error": tr(lang, "解析失败: %v", err),
 			}
 		}
+		if info.Kind == "Pod" {
+			info.Containers = podContainerDetails(info, lang)
+		}
+		info.SchemaIssues = validateCustomResource(info, lang)
 
 		infos = append(infos, info)
 	}
@@ -1056,8 +326,6 @@ func generateResourceInfo(resources []K8sResource) []ResourceInfo {
 	return infos
 }
 
-
-
 // 生成种类统计
 func generateKindStats(resources []K8sResource) []KindStat {
 	kindCounts := make(map[string]int)
@@ -1098,128 +366,219 @@ func countNamespaces(resources []K8sResource) int {
 	return len(namespaces)
 }
 
-func main() {
-	// 手动解析参数，避免影响 kubectl 参数
-	var host, port string = "localhost", "8000"
-	var kubectlArgs []string
+// 构造页面数据，报告中的说明文字使用 lang 语言
+func buildPageData(resources []K8sResource, command string, clean bool, lang string) PageData {
+	resourceInfos := generateResourceInfo(resources, clean, lang)
+	// 检查基于完整数据，精简视图会去掉 last-applied-configuration 注解
+	rawInfos := resourceInfos
+	if clean {
+		rawInfos = generateResourceInfo(resources, false, lang)
+	}
+	// 解析证书并附加到资源信息上，需在生成 JSON 之前完成
+	certificates := buildCertificateReport(resourceInfos, lang)
+	references := buildReferenceReport(resourceInfos, lang)
+	gitOps := buildGitOpsReport(resourceInfos, lang)
 
-	// 解析自定义参数
-	args := os.Args[1:]
-	i := 0
-	for i < len(args) {
-		switch args[i] {
-		case "-host":
-			if i+1 < len(args) {
-				host = args[i+1]
-				i += 2
-			} else {
-				log.Fatal("错误: -host 参数需要一个值")
-			}
-		case "-port":
-			if i+1 < len(args) {
-				port = args[i+1]
-				i += 2
-			} else {
-				log.Fatal("错误: -port 参数需要一个值")
-			}
-		case "-help", "--help", "-h":
-			fmt.Println("kubectl-html - Kubernetes 资源可视化工具")
-			fmt.Println("")
-			fmt.Println("用法:")
-			fmt.Println("  kubectl-html [选项] [kubectl参数...]")
-			fmt.Println("")
-			fmt.Println("选项:")
-			fmt.Println("  -host string    服务器监听地址 (默认: localhost)")
-			fmt.Println("                  localhost - 仅本机访问")
-			fmt.Println("                  0.0.0.0   - 允许外部访问")
-			fmt.Println("                  具体IP    - 绑定到指定网卡")
-			fmt.Println("  -port string    服务器监听端口 (默认: 8000)")
-			fmt.Println("  -help           显示此帮助信息")
-			fmt.Println("")
-			fmt.Println("示例:")
-			fmt.Println("  kubectl-html get pods")
-			fmt.Println("  kubectl-html -host 0.0.0.0 get pods")
-			fmt.Println("  kubectl-html -host 0.0.0.0 -port 9000 get deployments -A")
-			fmt.Println("  kubectl-html get po,svc,deploy -n kube-system")
-			fmt.Println("")
-			fmt.Println("安全提示:")
-			fmt.Println("  使用 0.0.0.0 会允许网络中的其他设备访问")
-			fmt.Println("  请确保网络环境安全，或使用防火墙限制访问")
-			return
-		default:
-			// 其他参数都是 kubectl 参数
-			kubectlArgs = append(kubectlArgs, args[i])
-			i++
-		}
+	// 将资源信息转换为 JSON 供前端使用
+	resourcesJSON, err := json.Marshal(resourceInfos)
+	if err != nil {
+		log.Printf("⚠️  Warning: Failed to marshal resources to JSON: %v", err)
+		resourcesJSON = []byte("[]")
 	}
 
-	if len(kubectlArgs) == 0 {
-		log.Fatal("错误: 需要提供 kubectl 参数\n\n" +
-			"用法: kubectl-html [选项] [kubectl参数...]\n" +
-			"示例: kubectl-html get pods\n" +
-			"帮助: kubectl-html -help")
+	return PageData{
+		Command:        command,
+		Timestamp:      time.Now().Format("2006-01-02 15:04:05 MST"),
+		TotalResources: len(resources),
+		NamespaceCount: countNamespaces(resources),
+		CleanView:      clean,
+		Resources:      resourceInfos,
+		KindStats:      generateKindStats(resources),
+		LabelIndex:     buildLabelIndex(resourceInfos, lang),
+		Images:         buildImageInventory(resourceInfos, allIndices(resourceInfos)),
+		Capacity:       buildCapacityReport(resourceInfos, lang),
+		Lint:           buildLintReport(rawInfos, lang),
+		Deprecations:   buildDeprecationReport(rawInfos),
+		PodSecurity:    buildPSSReport(resourceInfos, lang),
+		Certificates:   certificates,
+		References:     references,
+		Storage:        buildStorageReport(resourceInfos, lang),
+		Routes:         buildRouteMap(resourceInfos, lang),
+		Helm:           buildHelmReport(resourceInfos, lang),
+		GitOps:         gitOps,
+		Nodes:          buildNodeDashboard(resourceInfos),
+		ResourcesJSON:  template.JS(resourcesJSON),
+		Lang:           lang,
+		Languages:      languages,
+		Messages:       catalogJSON(lang),
 	}
+}
 
-	// 构造 kubectl 命令
-	kubectlArgs = append(kubectlArgs, "-o", "yaml")
+// 解析 ?clean= 参数，未指定时使用默认值
+func cleanViewParam(r *http.Request, def bool) bool {
+	switch r.URL.Query().Get("clean") {
+	case "":
+		return def
+	case "0", "false", "off":
+		return false
+	default:
+		return true
+	}
+}
 
-	cmd := exec.Command("kubectl", kubectlArgs...)
-	var outBuf, errBuf bytes.Buffer
-	cmd.Stdout = &outBuf
-	cmd.Stderr = &errBuf
+func main() {
+	args := os.Args[1:]
 
-	log.Printf("🚀 Running: kubectl %s", strings.Join(kubectlArgs, " "))
-	if err := cmd.Run(); err != nil {
-		log.Fatalf("❌ kubectl failed: %v\nStderr: %s", err, errBuf.String())
+	// 先确定语言，之后的帮助信息和错误提示都按该语言输出
+	langFlag := cliLanguage(args)
+	if defaultLang = detectLanguage(langFlag); defaultLang == "" {
+		defaultLang = langZH
+		log.Fatal(tr(defaultLang, "错误: %v", errorf("--lang 可选 zh、en: %s", langFlag)))
 	}
 
-	yamlData := outBuf.String()
-	if yamlData == "" {
-		log.Fatal("❌ No data returned from kubectl")
+	cfg, err := parseCLI(args)
+	if err != nil {
+		log.Fatal(tr(defaultLang, "错误: %v", err))
+	}
+	if cfg.help {
+		printUsage(os.Stdout, cfg.command, defaultLang)
+		return
 	}
 
-	// 解析 Kubernetes 资源
-	resources, err := parseKubernetesYAML(yamlData)
-	if err != nil {
-		log.Printf("⚠️  Warning: Failed to parse YAML structure: %v", err)
-		// 继续使用原始 YAML
+	switch cfg.command {
+	case cmdCompletion:
+		var script string
+		if len(cfg.args) == 1 {
+			script = completionScripts[cfg.args[0]]
+		}
+		if script == "" {
+			log.Fatal(tr(defaultLang, "错误: completion 需要指定 shell: bash、zsh、fish"))
+		}
+		fmt.Print(script)
+		return
+	case cmdComplete:
+		writeCompletions(os.Stdout, cfg.args)
+		return
+	case cmdDiff:
+		if cfg.diffFrom == "" {
+			log.Fatal(tr(defaultLang, "错误: diff 需要通过 --from 指定快照文件"))
+		}
+	}
+
+	var resources []K8sResource
+	// 页面和 diff 中显示的命令：用户给出的 kubectl 参数，不含追加的 -o yaml
+	command := shellQuote(cfg.kubectlArgs)
+	var kubectlArgs []string
+	if len(cfg.fromFiles) > 0 {
+		// 本地清单按原本的写法检查，不访问集群，字段说明只使用缓存和内置文档包
+		if len(cfg.kubectlArgs) > 0 {
+			log.Fatal(tr(defaultLang, "错误: --from-file 不能与 kubectl 参数同时使用: %s", command))
+		}
+		var displayArgs []string
+		for _, path := range cfg.fromFiles {
+			displayArgs = append(displayArgs, "-f", path)
+		}
+		command = "html " + shellQuote(displayArgs)
+		cfg.fetchCRDs, cfg.fetchOpenAPI = false, false
+
+		log.Printf("📂 Reading manifests: %s", strings.Join(cfg.fromFiles, ", "))
+		resources, err = loadManifestFiles(cfg.fromFiles)
+		if err != nil {
+			log.Fatalf("❌ Failed to read manifests: %v", err)
+		}
+	} else {
+		if len(cfg.kubectlArgs) == 0 {
+			log.Fatal(tr(defaultLang, "错误: 需要提供 kubectl 参数") + "\n\n" +
+				tr(defaultLang, "用法: kubectl-html [子命令] [选项] [--] <kubectl参数...>") + "\n" +
+				tr(defaultLang, "示例: kubectl-html get pods") + "\n" +
+				tr(defaultLang, "帮助: kubectl-html --help"))
+		}
+
+		// 构造 kubectl 命令
+		kubectlArgs = append(append([]string{}, cfg.kubectlArgs...), "-o", "yaml")
+
+		cmd := exec.Command("kubectl", kubectlArgs...)
+		var outBuf, errBuf bytes.Buffer
+		cmd.Stdout = &outBuf
+		cmd.Stderr = &errBuf
+
+		log.Printf("🚀 Running: kubectl %s", shellQuote(kubectlArgs))
+		if err := cmd.Run(); err != nil {
+			log.Fatalf("❌ kubectl failed: %v\nStderr: %s", err, errBuf.String())
+		}
+
+		yamlData := outBuf.String()
+		if yamlData == "" {
+			log.Fatal("❌ No data returned from kubectl")
+		}
+
+		// 解析 Kubernetes 资源
+		resources, err = parseKubernetesYAML(yamlData)
+		if err != nil {
+			log.Printf("⚠️  Warning: Failed to parse YAML structure: %v", err)
+			// 继续使用原始 YAML
+		}
 	}
 
 	log.Printf("📦 Parsed %d resources", len(resources))
 
-	// 生成各种数据
-	resourceInfos := generateResourceInfo(resources)
+	// 自定义资源按 CRD schema 显示字段说明并校验
+	loadCRDSchemas(resources, kubectlArgs, cfg.fetchCRDs)
+
+	switch cfg.command {
+	case cmdLint:
+		// CI 模式：输出检查结果，存在达到阈值的问题时以非 0 退出
+		findings := runLint(generateResourceInfo(resources, false, defaultLang), defaultLang)
+		if failures := printLintFindings(os.Stdout, findings, cfg.lintFailOn, defaultLang); failures > 0 {
+			log.Printf("❌ %d findings at or above %s", failures, cfg.lintFailOn)
+			os.Exit(1)
+		}
+		log.Printf("✅ Lint passed")
+		return
+	case cmdExport:
+		runExport(cfg, resources)
+		return
+	case cmdDiff:
+		runDiff(cfg, resources, command)
+		return
+	}
+
+	// 内置资源的字段说明来自集群 OpenAPI v3
+	loadBuiltinDocs(resources, kubectlArgs, cfg.fetchOpenAPI)
+
 	kindStats := generateKindStats(resources)
 	namespaceCount := countNamespaces(resources)
 
-	// 将资源信息转换为 JSON 供前端使用
-	resourcesJSON, err := json.Marshal(resourceInfos)
-	if err != nil {
-		log.Printf("⚠️  Warning: Failed to marshal resources to JSON: %v", err)
-		resourcesJSON = []byte("[]")
+	// 精简视图与完整视图在每种语言下各生成一份页面数据，通过 ?clean= 和 ?locale= 切换
+	type pageKey struct {
+		clean bool
+		lang  string
 	}
-
-	// 构造页面数据
-	data := PageData{
-		Command:        strings.Join(os.Args[2:], " "),
-		Timestamp:      time.Now().Format("2006-01-02 15:04:05 MST"),
-		TotalResources: len(resources),
-		NamespaceCount: namespaceCount,
-		Resources:      resourceInfos,
-		KindStats:      kindStats,
-		ResourcesJSON:  template.JS(resourcesJSON),
+	pages := make(map[pageKey]PageData)
+	for _, l := range languages {
+		for _, clean := range []bool{true, false} {
+			pages[pageKey{clean, l.Code}] = buildPageData(resources, command, clean, l.Code)
+		}
+	}
+	// 启动前先解析一次模板，覆盖目录中的模板有误时尽早报错
+	if _, err := loadPageTemplate(defaultLang); err != nil {
+		log.Fatalf("❌ Template error: %v", err)
+	}
+	pageFor := func(r *http.Request) PageData {
+		return pages[pageKey{cleanViewParam(r, cfg.cleanView), requestLanguage(r)}]
 	}
 
 	// 启动 HTTP 服务器
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		tmpl, err := template.New("index").Parse(htmlTemplate)
+		page := pageFor(r)
+		tmpl, err := loadPageTemplate(page.Lang)
 		if err != nil {
 			http.Error(w, "Template error", http.StatusInternalServerError)
 			log.Printf("❌ Template error: %v", err)
 			return
 		}
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		if err := tmpl.Execute(w, data); err != nil {
+		if err := tmpl.Execute(w, page); err != nil {
 			log.Printf("❌ Template execution error: %v", err)
 		}
 	})
@@ -1227,31 +586,38 @@ func main() {
 	// 添加 API 端点用于获取 JSON 数据
 	http.HandleFunc("/api/resources", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(data)
+		json.NewEncoder(w).Encode(pageFor(r))
 	})
 
+	// 版本化 REST API
+	registerAPIv1(pageFor)
+
+	// 导出端点始终基于完整数据，由 clean 参数决定是否生成可重新 apply 的清单
+	http.HandleFunc("/api/export", exportHandler(pages[pageKey{false, defaultLang}].Resources))
+
 	// 构造监听地址
+	host, port := cfg.host, cfg.port
 	listenAddr := host + ":" + port
-	
-	fmt.Printf("\n✅ Kubernetes 资源查看器已启动!\n")
-	
+
+	fmt.Printf("\n%s\n", tr(defaultLang, "✅ Kubernetes 资源查看器已启动!"))
+
 	// 显示访问地址
 	if host == "0.0.0.0" {
-		fmt.Printf("🌐 Web界面: \n")
-		fmt.Printf("   本机访问: http://localhost:%s\n", port)
-		fmt.Printf("   网络访问: http://<你的IP>:%s\n", port)
-		fmt.Printf("⚠️  警告: 允许外部网络访问，请确保网络安全!\n")
+		fmt.Println(tr(defaultLang, "🌐 Web界面:"))
+		fmt.Println("   " + tr(defaultLang, "本机访问: http://localhost:%s", port))
+		fmt.Println("   " + tr(defaultLang, "网络访问: http://<你的IP>:%s", port))
+		fmt.Println(tr(defaultLang, "⚠️  警告: 允许外部网络访问，请确保网络安全!"))
 	} else if host == "localhost" || host == "127.0.0.1" {
-		fmt.Printf("🌐 Web界面: http://localhost:%s\n", port)
+		fmt.Println(tr(defaultLang, "🌐 Web界面: http://%s:%s", "localhost", port))
 	} else {
-		fmt.Printf("🌐 Web界面: http://%s:%s\n", host, port)
+		fmt.Println(tr(defaultLang, "🌐 Web界面: http://%s:%s", host, port))
 	}
-	
-	fmt.Printf("📦 资源总数: %d\n", len(resources))
-	fmt.Printf("🏷️  资源类型: %d\n", len(kindStats))
-	fmt.Printf("📁 命名空间: %d\n", namespaceCount)
-	fmt.Printf("🎯 监听地址: %s\n", listenAddr)
-	fmt.Printf("\n按 Ctrl+C 退出\n\n")
-	
+
+	fmt.Println(tr(defaultLang, "📦 资源总数: %d", len(resources)))
+	fmt.Println(tr(defaultLang, "🏷️  资源类型: %d", len(kindStats)))
+	fmt.Println(tr(defaultLang, "📁 命名空间: %d", namespaceCount))
+	fmt.Println(tr(defaultLang, "🎯 监听地址: %s", listenAddr))
+	fmt.Printf("\n%s\n\n", tr(defaultLang, "按 Ctrl+C 退出"))
+
 	log.Fatal(http.ListenAndServe(listenAddr, nil))
-}
\ No newline at end of file
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 目录中读取的清单文件扩展名
+var manifestExtensions = map[string]bool{".yaml": true, ".yml": true, ".json": true}
+
+// 读取本地清单：文件、目录（递归读取其中的 YAML、JSON 文件）或 - 表示的标准输入，
+// 与 diff --from 的快照一样按原本的写法解析，不经过 kubectl
+func loadManifestFiles(paths []string) ([]K8sResource, error) {
+	var resources []K8sResource
+	for _, path := range paths {
+		files, err := manifestFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			var data []byte
+			if file == "-" {
+				data, err = io.ReadAll(os.Stdin)
+			} else {
+				data, err = os.ReadFile(file)
+			}
+			if err != nil {
+				return nil, err
+			}
+			parsed, err := parseKubernetesYAML(string(data))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", file, err)
+			}
+			resources = append(resources, parsed...)
+		}
+	}
+	return resources, nil
+}
+
+// 展开路径：目录按文件名顺序列出其中的清单文件，跳过 .git 等隐藏目录
+func manifestFiles(path string) ([]string, error) {
+	if path == "-" {
+		return []string{path}, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	var files []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != path && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if manifestExtensions[strings.ToLower(filepath.Ext(p))] {
+			files = append(files, p)
+		}
+		return nil
+	})
+	return files, err
+}
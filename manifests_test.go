@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadManifestFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "a.yaml"), `apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: web
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: backup
+`)
+	writeTestFile(t, filepath.Join(dir, "sub", "b.json"), `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "config"}}`)
+	writeTestFile(t, filepath.Join(dir, "sub", "c.YML"), "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\n")
+	writeTestFile(t, filepath.Join(dir, "notes.txt"), "apiVersion: v1\nkind: Secret\n")
+	writeTestFile(t, filepath.Join(dir, ".git", "d.yaml"), "apiVersion: v1\nkind: Secret\n")
+	single := filepath.Join(dir, "single.txt")
+	writeTestFile(t, single, "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\n")
+
+	tests := []struct {
+		paths []string
+		want  []string
+	}{
+		{[]string{dir}, []string{"Ingress", "CronJob", "ConfigMap", "Service"}},
+		{[]string{filepath.Join(dir, "sub")}, []string{"ConfigMap", "Service"}},
+		{[]string{single, filepath.Join(dir, "a.yaml")}, []string{"Deployment", "Ingress", "CronJob"}},
+	}
+	for _, tt := range tests {
+		resources, err := loadManifestFiles(tt.paths)
+		if err != nil {
+			t.Errorf("loadManifestFiles(%q) 返回错误: %v", tt.paths, err)
+			continue
+		}
+		var kinds []string
+		for _, r := range resources {
+			kinds = append(kinds, r.Kind)
+		}
+		if !reflect.DeepEqual(kinds, tt.want) {
+			t.Errorf("loadManifestFiles(%q) = %v, 期望 %v", tt.paths, kinds, tt.want)
+		}
+	}
+
+	if _, err := loadManifestFiles([]string{filepath.Join(dir, "missing.yaml")}); err == nil {
+		t.Errorf("读取不存在的文件期望返回错误")
+	}
+}
+
+// 本地清单按原本的 apiVersion 检查，而不是集群返回的首选版本
+func TestManifestDeprecations(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "ingress.yaml"), `apiVersion: networking.k8s.io/v1beta1
+kind: Ingress
+metadata:
+  name: web
+  namespace: default
+`)
+	resources, err := loadManifestFiles([]string{dir})
+	if err != nil {
+		t.Fatalf("loadManifestFiles 返回错误: %v", err)
+	}
+	infos := generateResourceInfo(resources, false, langZH)
+	if len(infos) != 1 {
+		t.Fatalf("解析出 %d 个资源, 期望 1 个", len(infos))
+	}
+	usages := deprecatedAPIUsages(infos[0], "1.22")
+	if len(usages) != 1 || !usages[0].Removed || usages[0].Replacement != "networking.k8s.io/v1" {
+		t.Errorf("deprecatedAPIUsages = %+v, 期望 1.22 中已移除并由 networking.k8s.io/v1 替代", usages)
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 节点仪表盘展示的资源类型
+var nodeResourceNames = []string{"cpu", "memory", "ephemeral-storage", "pods"}
+
+type NodeCondition struct {
+	Type    string
+	Status  string
+	Reason  string
+	Message string
+	Problem bool // Ready 以外的条件为 True 或 Ready 不为 True
+}
+
+// 单项资源的容量与使用情况
+type NodeResourceUsage struct {
+	Resource       string
+	Capacity       string
+	Allocatable    string
+	Requests       string
+	Limits         string
+	RequestPercent int // 占 allocatable 的百分比，-1 表示无法计算
+	LimitPercent   int
+}
+
+// 进度条宽度，超过 100% 时截断
+func (u NodeResourceUsage) RequestBar() int { return barWidth(u.RequestPercent) }
+func (u NodeResourceUsage) LimitBar() int   { return barWidth(u.LimitPercent) }
+
+func barWidth(percent int) int {
+	if percent > 100 {
+		return 100
+	}
+	if percent < 0 {
+		return 0
+	}
+	return percent
+}
+
+// 按使用率返回进度条颜色等级
+func usageLevel(percent int) string {
+	switch {
+	case percent >= 90:
+		return "high"
+	case percent >= 70:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+func (u NodeResourceUsage) RequestLevel() string { return usageLevel(u.RequestPercent) }
+func (u NodeResourceUsage) LimitLevel() string   { return usageLevel(u.LimitPercent) }
+
+type NodeSummary struct {
+	Index            int
+	Name             string
+	Age              string
+	Ready            string // True、False 或 Unknown
+	Unschedulable    bool
+	Roles            []string
+	Conditions       []NodeCondition
+	Pressures        []string
+	Taints           []string
+	KubeletVersion   string
+	OSImage          string
+	OS               string
+	Arch             string
+	KernelVersion    string
+	ContainerRuntime string
+	Addresses        []string
+	Pods             int
+	Usage            []NodeResourceUsage
+}
+
+type NodeDashboard struct {
+	Nodes      []NodeSummary
+	PodsLoaded bool
+	ReadyCount int
+}
+
+// 节点角色来自 node-role.kubernetes.io/<role> 和 kubernetes.io/role 标签
+func nodeRoles(labels map[string]string) []string {
+	var roles []string
+	for key, value := range labels {
+		if strings.HasPrefix(key, "node-role.kubernetes.io/") {
+			roles = append(roles, strings.TrimPrefix(key, "node-role.kubernetes.io/"))
+		} else if key == "kubernetes.io/role" && value != "" {
+			roles = append(roles, value)
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+func usagePercent(used, allocatable float64) int {
+	if allocatable <= 0 {
+		return -1
+	}
+	return int(math.Round(used / allocatable * 100))
+}
+
+// 汇总节点状态，并统计已加载 Pod 在各节点上的数量和 requests/limits
+func buildNodeDashboard(infos []ResourceInfo) NodeDashboard {
+	var dashboard NodeDashboard
+	type podUsage struct {
+		count            int
+		requests, limits ResourceAmounts
+	}
+	usage := make(map[string]*podUsage)
+	for _, info := range infos {
+		if info.Kind != "Pod" {
+			continue
+		}
+		dashboard.PodsLoaded = true
+		nodeName := nestedString(info.Parsed, "spec", "nodeName")
+		if nodeName == "" || podFinished(info) {
+			continue
+		}
+		u, ok := usage[nodeName]
+		if !ok {
+			u = &podUsage{requests: make(ResourceAmounts), limits: make(ResourceAmounts)}
+			usage[nodeName] = u
+		}
+		requests, limits := podAmounts(podSpec(info))
+		u.count++
+		u.requests.add(requests, 1)
+		u.limits.add(limits, 1)
+	}
+
+	for i, info := range infos {
+		if info.Kind != "Node" {
+			continue
+		}
+		node := NodeSummary{
+			Index:            i,
+			Name:             info.Name,
+			Age:              info.Age,
+			Ready:            "Unknown",
+			Unschedulable:    nestedValue(info.Parsed, "spec", "unschedulable") == true,
+			Roles:            nodeRoles(resourceLabels(info)),
+			KubeletVersion:   nestedString(info.Parsed, "status", "nodeInfo", "kubeletVersion"),
+			OSImage:          nestedString(info.Parsed, "status", "nodeInfo", "osImage"),
+			OS:               nestedString(info.Parsed, "status", "nodeInfo", "operatingSystem"),
+			Arch:             nestedString(info.Parsed, "status", "nodeInfo", "architecture"),
+			KernelVersion:    nestedString(info.Parsed, "status", "nodeInfo", "kernelVersion"),
+			ContainerRuntime: nestedString(info.Parsed, "status", "nodeInfo", "containerRuntimeVersion"),
+		}
+
+		for _, item := range nestedSlice(info.Parsed, "status", "conditions") {
+			c, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			cond := NodeCondition{
+				Type:    nestedString(c, "type"),
+				Status:  nestedString(c, "status"),
+				Reason:  nestedString(c, "reason"),
+				Message: nestedString(c, "message"),
+			}
+			if cond.Type == "Ready" {
+				node.Ready = cond.Status
+				cond.Problem = cond.Status != "True"
+			} else if cond.Status == "True" {
+				cond.Problem = true
+				node.Pressures = append(node.Pressures, cond.Type)
+			}
+			node.Conditions = append(node.Conditions, cond)
+		}
+		if node.Ready == "True" {
+			dashboard.ReadyCount++
+		}
+
+		for _, item := range nestedSlice(info.Parsed, "spec", "taints") {
+			t, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			taint := nestedString(t, "key")
+			if v := nestedString(t, "value"); v != "" {
+				taint += "=" + v
+			}
+			node.Taints = append(node.Taints, taint+":"+nestedString(t, "effect"))
+		}
+		for _, item := range nestedSlice(info.Parsed, "status", "addresses") {
+			if a, ok := item.(map[string]interface{}); ok {
+				node.Addresses = append(node.Addresses, nestedString(a, "type")+": "+nestedString(a, "address"))
+			}
+		}
+
+		capacity := nestedMap(info.Parsed, "status", "capacity")
+		allocatable := nestedMap(info.Parsed, "status", "allocatable")
+		u := usage[info.Name]
+		if u == nil {
+			u = &podUsage{requests: make(ResourceAmounts), limits: make(ResourceAmounts)}
+		}
+		node.Pods = u.count
+		for _, name := range nodeResourceNames {
+			row := NodeResourceUsage{
+				Resource:       name,
+				Capacity:       stringValue(capacity[name]),
+				Allocatable:    stringValue(allocatable[name]),
+				RequestPercent: -1,
+				LimitPercent:   -1,
+			}
+			alloc, err := parseQuantity(row.Allocatable)
+			if err != nil {
+				alloc = 0
+			}
+			if dashboard.PodsLoaded {
+				if name == "pods" {
+					row.Requests = strconv.Itoa(u.count)
+					row.RequestPercent = usagePercent(float64(u.count), alloc)
+				} else {
+					row.Requests = formatAmount(name, u.requests[name])
+					row.Limits = formatAmount(name, u.limits[name])
+					row.RequestPercent = usagePercent(u.requests[name], alloc)
+					row.LimitPercent = usagePercent(u.limits[name], alloc)
+				}
+			}
+			node.Usage = append(node.Usage, row)
+		}
+		dashboard.Nodes = append(dashboard.Nodes, node)
+	}
+	return dashboard
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 离线文档包：精简的 Kubernetes OpenAPI v3 文档，只包含常用内置资源的常用字段，
+// 无法访问集群的 OpenAPI 且没有磁盘缓存时使用
+//
+//go:embed openapi-fallback.json
+var fallbackOpenAPI []byte
+
+// schema 来源，前端据此显示说明
+const (
+	schemaSourceCRD     = "crd"
+	schemaSourceCluster = "cluster"
+	schemaSourceCache   = "cache"
+	schemaSourceBundled = "bundled"
+)
+
+// 一份 OpenAPI v3 文档中的 schema 定义
+type openAPIDocument struct {
+	source  string
+	version string
+	schemas map[string]interface{}
+	kinds   map[string]string // "apiVersion kind" -> 定义名称
+}
+
+func parseOpenAPIDocument(data []byte, source string) (*openAPIDocument, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	doc := &openAPIDocument{
+		source:  source,
+		version: nestedString(raw, "info", "version"),
+		schemas: nestedMap(raw, "components", "schemas"),
+		kinds:   make(map[string]string),
+	}
+	for name, value := range doc.schemas {
+		def, _ := value.(map[string]interface{})
+		for _, item := range nestedSlice(def, "x-kubernetes-group-version-kind") {
+			gvk, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			apiVersion := nestedString(gvk, "version")
+			if group := nestedString(gvk, "group"); group != "" {
+				apiVersion = group + "/" + apiVersion
+			}
+			doc.kinds[schemaKey(apiVersion, nestedString(gvk, "kind"))] = name
+		}
+	}
+	return doc, nil
+}
+
+func (d *openAPIDocument) resolve(ref string) map[string]interface{} {
+	def, _ := d.schemas[strings.TrimPrefix(ref, "#/components/schemas/")].(map[string]interface{})
+	return def
+}
+
+// 内置资源的字段文档，按加载顺序查找：集群 OpenAPI（含磁盘缓存）优先，离线文档包兜底
+var builtinDocs = struct {
+	sync.Mutex
+	docs  []*openAPIDocument
+	built map[string]*builtinSchema
+}{built: make(map[string]*builtinSchema)}
+
+type builtinSchema struct {
+	node *SchemaNode
+	doc  *openAPIDocument
+}
+
+// 集群 OpenAPI v3 中资源组版本对应的路径，如 api/v1、apis/apps/v1
+func openAPIPath(apiVersion string) string {
+	if apiGroup(apiVersion) == "" {
+		return "api/" + apiVersion
+	}
+	return "apis/" + apiVersion
+}
+
+func openAPICacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kubectl-html", "openapi")
+}
+
+// 缓存文件名包含服务端给出的内容哈希，集群升级后哈希变化会重新获取
+func openAPICacheFile(dir, path, hash string) string {
+	return filepath.Join(dir, strings.ReplaceAll(path, "/", "_")+"-"+hash+".json")
+}
+
+// 同一组版本最近写入的缓存，集群不可用时使用
+func latestOpenAPICache(dir, path string) string {
+	matches, _ := filepath.Glob(filepath.Join(dir, strings.ReplaceAll(path, "/", "_")+"-*.json"))
+	latest, latestTime := "", int64(0)
+	for _, m := range matches {
+		if st, err := os.Stat(m); err == nil && st.ModTime().UnixNano() > latestTime {
+			latest, latestTime = m, st.ModTime().UnixNano()
+		}
+	}
+	return latest
+}
+
+// 获取一个组版本的 OpenAPI 文档；serverURL 为空表示只使用磁盘缓存
+func fetchOpenAPIDocument(path, serverURL, cacheDir string, connArgs []string) (*openAPIDocument, error) {
+	if serverURL == "" {
+		file := latestOpenAPICache(cacheDir, path)
+		if cacheDir == "" || file == "" {
+			return nil, errorf("没有 %s 的缓存", path)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		return parseOpenAPIDocument(data, schemaSourceCache)
+	}
+
+	hash := ""
+	if u, err := url.Parse(serverURL); err == nil {
+		hash = u.Query().Get("hash")
+	}
+	if cacheDir != "" && hash != "" {
+		if data, err := os.ReadFile(openAPICacheFile(cacheDir, path, hash)); err == nil {
+			return parseOpenAPIDocument(data, schemaSourceCluster)
+		}
+	}
+	data, err := runKubectl(append([]string{"get", "--raw", serverURL}, connArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := parseOpenAPIDocument(data, schemaSourceCluster)
+	if err != nil {
+		return nil, err
+	}
+	if cacheDir != "" && hash != "" {
+		if err := os.MkdirAll(cacheDir, 0o755); err == nil {
+			if err := os.WriteFile(openAPICacheFile(cacheDir, path, hash), data, 0o644); err != nil {
+				log.Printf("⚠️  Warning: Failed to cache OpenAPI document: %v", err)
+			}
+		}
+	}
+	return doc, nil
+}
+
+// 为已加载的内置资源加载字段文档。fetch 为 false 时不访问集群，只使用磁盘缓存和离线文档包
+func loadBuiltinDocs(resources []K8sResource, kubectlArgs []string, fetch bool) {
+	paths := make(map[string]bool)
+	for _, resource := range resources {
+		if resource.Kind != "List" && builtinAPIGroups[apiGroup(resource.APIVersion)] {
+			paths[openAPIPath(resource.APIVersion)] = true
+		}
+	}
+	var sorted []string
+	for path := range paths {
+		sorted = append(sorted, path)
+	}
+	sort.Strings(sorted)
+
+	connArgs := kubectlConnectionArgs(kubectlArgs)
+	cacheDir := openAPICacheDir()
+	var index map[string]interface{}
+	if fetch && len(sorted) > 0 {
+		data, err := runKubectl(append([]string{"get", "--raw", "/openapi/v3"}, connArgs...)...)
+		if err == nil {
+			var raw map[string]interface{}
+			if err = json.Unmarshal(data, &raw); err == nil {
+				if index = nestedMap(raw, "paths"); index == nil {
+					err = errorf("响应中没有 paths")
+				}
+			}
+		}
+		if err != nil {
+			log.Printf("⚠️  Warning: Cluster OpenAPI v3 unavailable, using cached or bundled field docs: %v", err)
+		}
+	}
+
+	var docs []*openAPIDocument
+	for _, path := range sorted {
+		doc, err := fetchOpenAPIDocument(path, nestedString(index, path, "serverRelativeURL"), cacheDir, connArgs)
+		if err != nil {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+	if bundled, err := parseOpenAPIDocument(fallbackOpenAPI, schemaSourceBundled); err == nil {
+		docs = append(docs, bundled)
+	}
+	if len(docs) > 1 {
+		log.Printf("📖 Loaded field docs for %d API group versions", len(docs)-1)
+	}
+
+	builtinDocs.Lock()
+	builtinDocs.docs = docs
+	builtinDocs.Unlock()
+}
+
+// 查找资源类型的 schema：自定义资源使用 CRD，内置资源使用 OpenAPI 文档
+func lookupSchema(apiVersion, kind string) (*SchemaNode, string, string) {
+	key := schemaKey(apiVersion, kind)
+	if schema := crdSchemas[key]; schema != nil {
+		return schema, schemaSourceCRD, ""
+	}
+	builtinDocs.Lock()
+	defer builtinDocs.Unlock()
+	if b, ok := builtinDocs.built[key]; ok {
+		if b == nil {
+			return nil, "", ""
+		}
+		return b.node, b.doc.source, b.doc.version
+	}
+	var found *builtinSchema
+	for _, doc := range builtinDocs.docs {
+		if name, ok := doc.kinds[key]; ok {
+			def, _ := doc.schemas[name].(map[string]interface{})
+			found = &builtinSchema{node: schemaNodeFromMap(def, doc.resolve), doc: doc}
+			break
+		}
+	}
+	builtinDocs.built[key] = found
+	if found == nil {
+		return nil, "", ""
+	}
+	return found.node, found.doc.source, found.doc.version
+}
+
+// /api/v1/schema 的响应
+type SchemaResponse struct {
+	Source  string      `json:"source"`            // crd、cluster、cache 或 bundled
+	Version string      `json:"version,omitempty"` // 文档对应的 Kubernetes 版本
+	Schema  *SchemaNode `json:"schema"`
+}
+
+// GET /api/v1/schema?apiVersion=&kind= 返回资源类型的 schema，未知类型返回 404
+func apiSchemaHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	apiVersion, kind := query.Get("apiVersion"), query.Get("kind")
+	if apiVersion == "" || kind == "" {
+		writeAPIError(w, r, http.StatusBadRequest, "需要 apiVersion 和 kind 参数")
+		return
+	}
+	schema, source, version := lookupSchema(apiVersion, kind)
+	if schema == nil {
+		writeAPIError(w, r, http.StatusNotFound, "没有 %s %s 的 schema", apiVersion, kind)
+		return
+	}
+	writeJSON(w, http.StatusOK, SchemaResponse{Source: source, Version: version, Schema: schema})
+}
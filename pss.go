@@ -0,0 +1,405 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// Pod Security Standards 级别 https://kubernetes.io/docs/concepts/security/pod-security-standards/
+const (
+	pssPrivileged = "privileged"
+	pssBaseline   = "baseline"
+	pssRestricted = "restricted"
+)
+
+// 级别从宽松到严格的顺序
+var pssLevelRank = map[string]int{
+	pssPrivileged: 0,
+	pssBaseline:   1,
+	pssRestricted: 2,
+}
+
+// 命名空间上的 Pod Security Admission 标签
+const pssEnforceLabel = "pod-security.kubernetes.io/enforce"
+
+// baseline 允许添加的 capabilities
+var pssBaselineCapabilities = []string{
+	"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD",
+	"NET_BIND_SERVICE", "SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_CHROOT",
+}
+
+// baseline 允许的 sysctls
+var pssSafeSysctls = []string{
+	"kernel.shm_rmid_forced",
+	"net.ipv4.ip_local_port_range",
+	"net.ipv4.ip_unprivileged_port_start",
+	"net.ipv4.tcp_syncookies",
+	"net.ipv4.ping_group_range",
+	"net.ipv4.ip_local_reserved_ports",
+	"net.ipv4.tcp_keepalive_time",
+	"net.ipv4.tcp_fin_timeout",
+	"net.ipv4.tcp_keepalive_intvl",
+	"net.ipv4.tcp_keepalive_probes",
+}
+
+// baseline 允许的 SELinux type
+var pssSELinuxTypes = []string{"", "container_t", "container_init_t", "container_kvm_t", "container_engine_t"}
+
+// restricted 允许的卷类型
+var pssRestrictedVolumeTypes = []string{
+	"configMap", "csi", "downwardAPI", "emptyDir", "ephemeral",
+	"persistentVolumeClaim", "projected", "secret",
+}
+
+// 一项未通过的检查
+type PSSViolation struct {
+	Level  string
+	Check  string
+	Detail string
+}
+
+// 单个 Pod 模板的评估结果
+type PSSResult struct {
+	Index      int
+	Kind       string
+	Namespace  string
+	Name       string
+	Level      string // 满足的最严格级别
+	Violations []PSSViolation
+}
+
+// Pod 与各容器的 securityContext，容器级设置优先
+type pssPod struct {
+	spec        map[string]interface{}
+	annotations map[string]string
+	containers  []PodContainer
+	lang        string // 失败原因使用的语言
+}
+
+func (p pssPod) containerValue(c PodContainer, path ...string) interface{} {
+	if v := nestedValue(c.Spec, append([]string{"securityContext"}, path...)...); v != nil {
+		return v
+	}
+	return nestedValue(p.spec, append([]string{"securityContext"}, path...)...)
+}
+
+// 单项检查，返回失败原因
+type pssCheck struct {
+	level string
+	name  string
+	check func(p pssPod) []string
+}
+
+var pssChecks = []pssCheck{
+	{pssBaseline, "HostProcess", func(p pssPod) []string {
+		var out []string
+		for _, c := range p.containers {
+			if p.containerValue(c, "windowsOptions", "hostProcess") == true {
+				out = append(out, tr(p.lang, "容器 %s 设置了 windowsOptions.hostProcess", c.Name))
+			}
+		}
+		return out
+	}},
+	{pssBaseline, "Host Namespaces", func(p pssPod) []string {
+		var out []string
+		for _, field := range []string{"hostNetwork", "hostPID", "hostIPC"} {
+			if nestedValue(p.spec, field) == true {
+				out = append(out, tr(p.lang, "%s 为 true", field))
+			}
+		}
+		return out
+	}},
+	{pssBaseline, "Privileged Containers", func(p pssPod) []string {
+		var out []string
+		for _, c := range p.containers {
+			if nestedValue(c.Spec, "securityContext", "privileged") == true {
+				out = append(out, tr(p.lang, "容器 %s 为特权容器", c.Name))
+			}
+		}
+		return out
+	}},
+	{pssBaseline, "Capabilities", func(p pssPod) []string {
+		var out []string
+		for _, c := range p.containers {
+			for _, capability := range nestedSlice(c.Spec, "securityContext", "capabilities", "add") {
+				if !containsString(pssBaselineCapabilities, stringValue(capability)) {
+					out = append(out, tr(p.lang, "容器 %s 添加了 %v", c.Name, capability))
+				}
+			}
+		}
+		return out
+	}},
+	{pssBaseline, "HostPath Volumes", func(p pssPod) []string {
+		var out []string
+		for _, item := range nestedSlice(p.spec, "volumes") {
+			if v, ok := item.(map[string]interface{}); ok && v["hostPath"] != nil {
+				out = append(out, tr(p.lang, "卷 %s 使用 hostPath", nestedString(v, "name")))
+			}
+		}
+		return out
+	}},
+	{pssBaseline, "Host Ports", func(p pssPod) []string {
+		var out []string
+		for _, c := range p.containers {
+			for _, item := range nestedSlice(c.Spec, "ports") {
+				if port, ok := item.(map[string]interface{}); ok && port["hostPort"] != nil && stringValue(port["hostPort"]) != "0" {
+					out = append(out, tr(p.lang, "容器 %s 使用 hostPort %v", c.Name, port["hostPort"]))
+				}
+			}
+		}
+		return out
+	}},
+	{pssBaseline, "AppArmor", func(p pssPod) []string {
+		var out []string
+		for key, value := range p.annotations {
+			if strings.HasPrefix(key, "container.apparmor.security.beta.kubernetes.io/") &&
+				value != "runtime/default" && !strings.HasPrefix(value, "localhost/") {
+				out = append(out, key+"="+value)
+			}
+		}
+		if nestedString(p.spec, "securityContext", "appArmorProfile", "type") == "Unconfined" {
+			out = append(out, tr(p.lang, "Pod appArmorProfile 为 Unconfined"))
+		}
+		for _, c := range p.containers {
+			if nestedString(c.Spec, "securityContext", "appArmorProfile", "type") == "Unconfined" {
+				out = append(out, tr(p.lang, "容器 %s appArmorProfile 为 Unconfined", c.Name))
+			}
+		}
+		sort.Strings(out)
+		return out
+	}},
+	{pssBaseline, "SELinux", func(p pssPod) []string {
+		var out []string
+		check := func(owner string, opts map[string]interface{}) {
+			if opts == nil {
+				return
+			}
+			if t := nestedString(opts, "type"); !containsString(pssSELinuxTypes, t) {
+				out = append(out, tr(p.lang, "%s seLinuxOptions.type 为 %s", owner, t))
+			}
+			if nestedString(opts, "user") != "" || nestedString(opts, "role") != "" {
+				out = append(out, tr(p.lang, "%s 设置了 seLinuxOptions.user/role", owner))
+			}
+		}
+		check("Pod", nestedMap(p.spec, "securityContext", "seLinuxOptions"))
+		for _, c := range p.containers {
+			check(tr(p.lang, "容器 %s", c.Name), nestedMap(c.Spec, "securityContext", "seLinuxOptions"))
+		}
+		return out
+	}},
+	{pssBaseline, "/proc Mount Type", func(p pssPod) []string {
+		var out []string
+		for _, c := range p.containers {
+			if m := nestedString(c.Spec, "securityContext", "procMount"); m != "" && m != "Default" {
+				out = append(out, tr(p.lang, "容器 %s procMount 为 %s", c.Name, m))
+			}
+		}
+		return out
+	}},
+	{pssBaseline, "Seccomp", func(p pssPod) []string {
+		var out []string
+		if nestedString(p.spec, "securityContext", "seccompProfile", "type") == "Unconfined" {
+			out = append(out, tr(p.lang, "Pod seccompProfile 为 Unconfined"))
+		}
+		for _, c := range p.containers {
+			if nestedString(c.Spec, "securityContext", "seccompProfile", "type") == "Unconfined" {
+				out = append(out, tr(p.lang, "容器 %s seccompProfile 为 Unconfined", c.Name))
+			}
+		}
+		return out
+	}},
+	{pssBaseline, "Sysctls", func(p pssPod) []string {
+		var out []string
+		for _, item := range nestedSlice(p.spec, "securityContext", "sysctls") {
+			if s, ok := item.(map[string]interface{}); ok && !containsString(pssSafeSysctls, nestedString(s, "name")) {
+				out = append(out, "sysctl "+nestedString(s, "name"))
+			}
+		}
+		return out
+	}},
+	{pssRestricted, "Volume Types", func(p pssPod) []string {
+		var out []string
+		for _, item := range nestedSlice(p.spec, "volumes") {
+			v, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for key := range v {
+				if key != "name" && !containsString(pssRestrictedVolumeTypes, key) {
+					out = append(out, tr(p.lang, "卷 %s 类型为 %s", nestedString(v, "name"), key))
+				}
+			}
+		}
+		return out
+	}},
+	{pssRestricted, "Privilege Escalation", func(p pssPod) []string {
+		var out []string
+		for _, c := range p.containers {
+			if nestedValue(c.Spec, "securityContext", "allowPrivilegeEscalation") != false {
+				out = append(out, tr(p.lang, "容器 %s 未设置 allowPrivilegeEscalation: false", c.Name))
+			}
+		}
+		return out
+	}},
+	{pssRestricted, "Running as Non-root", func(p pssPod) []string {
+		var out []string
+		// Pod 级显式设置为 false 时即使每个容器都覆盖为 true 也不满足
+		if nestedValue(p.spec, "securityContext", "runAsNonRoot") == false {
+			out = append(out, tr(p.lang, "Pod runAsNonRoot 为 false"))
+		}
+		for _, c := range p.containers {
+			if p.containerValue(c, "runAsNonRoot") != true {
+				out = append(out, tr(p.lang, "容器 %s 未设置 runAsNonRoot: true", c.Name))
+			}
+		}
+		return out
+	}},
+	{pssRestricted, "Running as Non-root user", func(p pssPod) []string {
+		var out []string
+		if v := nestedValue(p.spec, "securityContext", "runAsUser"); v != nil && stringValue(v) == "0" {
+			out = append(out, tr(p.lang, "Pod runAsUser 为 0"))
+		}
+		for _, c := range p.containers {
+			if v := nestedValue(c.Spec, "securityContext", "runAsUser"); v != nil && stringValue(v) == "0" {
+				out = append(out, tr(p.lang, "容器 %s runAsUser 为 0", c.Name))
+			}
+		}
+		return out
+	}},
+	{pssRestricted, "Seccomp", func(p pssPod) []string {
+		var out []string
+		for _, c := range p.containers {
+			t := stringValue(p.containerValue(c, "seccompProfile", "type"))
+			if t != "RuntimeDefault" && t != "Localhost" {
+				out = append(out, tr(p.lang, "容器 %s 未设置 seccompProfile 为 RuntimeDefault 或 Localhost", c.Name))
+			}
+		}
+		return out
+	}},
+	{pssRestricted, "Capabilities", func(p pssPod) []string {
+		var out []string
+		for _, c := range p.containers {
+			dropsAll := false
+			for _, capability := range nestedSlice(c.Spec, "securityContext", "capabilities", "drop") {
+				if stringValue(capability) == "ALL" {
+					dropsAll = true
+				}
+			}
+			if !dropsAll {
+				out = append(out, tr(p.lang, "容器 %s 未 drop ALL", c.Name))
+			}
+			for _, capability := range nestedSlice(c.Spec, "securityContext", "capabilities", "add") {
+				if stringValue(capability) != "NET_BIND_SERVICE" {
+					out = append(out, tr(p.lang, "容器 %s 添加了 %v", c.Name, capability))
+				}
+			}
+		}
+		return out
+	}},
+}
+
+// 按 Pod Security Standards 评估工作负载的 Pod 模板，失败原因使用 lang 语言
+func evaluatePodSecurity(info ResourceInfo, lang string) (PSSResult, bool) {
+	spec := podSpec(info)
+	if spec == nil {
+		return PSSResult{}, false
+	}
+	pod := pssPod{
+		spec:        spec,
+		annotations: stringMap(nestedMap(podTemplateMetadata(info), "annotations")),
+		containers:  podContainers(spec),
+		lang:        lang,
+	}
+	for _, item := range nestedSlice(spec, "ephemeralContainers") {
+		if c, ok := item.(map[string]interface{}); ok {
+			pod.containers = append(pod.containers, PodContainer{Name: nestedString(c, "name"), Spec: c})
+		}
+	}
+
+	result := PSSResult{Kind: info.Kind, Namespace: info.Namespace, Name: info.Name, Level: pssRestricted}
+	for _, check := range pssChecks {
+		for _, detail := range check.check(pod) {
+			result.Violations = append(result.Violations, PSSViolation{Level: check.level, Check: check.name, Detail: detail})
+			// 未通过 baseline 检查只满足 privileged，未通过 restricted 检查只满足 baseline
+			if satisfied := pssLevelBelow(check.level); pssLevelRank[satisfied] < pssLevelRank[result.Level] {
+				result.Level = satisfied
+			}
+		}
+	}
+	return result, true
+}
+
+// 比指定级别宽松一级的级别
+func pssLevelBelow(level string) string {
+	if level == pssRestricted {
+		return pssBaseline
+	}
+	return pssPrivileged
+}
+
+// 命名空间汇总
+type PSSNamespaceSummary struct {
+	Namespace      string
+	NamespaceIndex int    // 已加载的 Namespace 资源索引，-1 表示未加载
+	Enforce        string // pod-security.kubernetes.io/enforce 标签
+	Workloads      int
+	Counts         map[string]int
+	Weakest        string // 工作负载中最宽松的级别
+	Violating      int    // 不满足 enforce 级别的工作负载数
+}
+
+type PSSReport struct {
+	Results    []PSSResult
+	Namespaces []PSSNamespaceSummary
+}
+
+func buildPSSReport(infos []ResourceInfo, lang string) PSSReport {
+	var report PSSReport
+	namespaces := make(map[string]*PSSNamespaceSummary)
+	summary := func(ns string) *PSSNamespaceSummary {
+		s, ok := namespaces[ns]
+		if !ok {
+			s = &PSSNamespaceSummary{Namespace: ns, NamespaceIndex: -1, Counts: make(map[string]int)}
+			namespaces[ns] = s
+		}
+		return s
+	}
+
+	for i, info := range infos {
+		if info.Kind == "Namespace" {
+			s := summary(info.Name)
+			s.NamespaceIndex = i
+			s.Enforce = resourceLabels(info)[pssEnforceLabel]
+			continue
+		}
+		result, ok := evaluatePodSecurity(info, lang)
+		if !ok {
+			continue
+		}
+		result.Index = i
+		report.Results = append(report.Results, result)
+
+		s := summary(info.Namespace)
+		s.Workloads++
+		s.Counts[result.Level]++
+		if s.Weakest == "" || pssLevelRank[result.Level] < pssLevelRank[s.Weakest] {
+			s.Weakest = result.Level
+		}
+	}
+
+	// 与命名空间的 enforce 级别比较
+	for _, result := range report.Results {
+		s := namespaces[result.Namespace]
+		if rank, ok := pssLevelRank[s.Enforce]; ok && pssLevelRank[result.Level] < rank {
+			s.Violating++
+		}
+	}
+
+	for _, s := range namespaces {
+		report.Namespaces = append(report.Namespaces, *s)
+	}
+	sort.Slice(report.Namespaces, func(i, j int) bool {
+		return report.Namespaces[i].Namespace < report.Namespaces[j].Namespace
+	})
+	return report
+}
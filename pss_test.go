@@ -0,0 +1,132 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 满足 restricted 的容器和 Pod 级 securityContext，用例在此基础上修改
+const (
+	pssTestContainer  = `{"name": "app", "securityContext": {"allowPrivilegeEscalation": false, "capabilities": {"drop": ["ALL"]}}}`
+	pssTestPodContext = `{"runAsNonRoot": true, "seccompProfile": {"type": "RuntimeDefault"}}`
+)
+
+func TestEvaluatePodSecurity(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  string
+		level string
+		want  []string // 未通过的检查，级别/名称
+	}{
+		{
+			"Pod 级设置满足 restricted",
+			`{"securityContext": ` + pssTestPodContext + `, "containers": [` + pssTestContainer + `]}`,
+			pssRestricted, nil,
+		},
+		{
+			"容器级 runAsNonRoot: false 覆盖 Pod 级",
+			`{"securityContext": ` + pssTestPodContext + `, "containers": [
+				{"name": "app", "securityContext": {"runAsNonRoot": false, "allowPrivilegeEscalation": false, "capabilities": {"drop": ["ALL"]}}}]}`,
+			pssBaseline, []string{"restricted/Running as Non-root"},
+		},
+		{
+			"只在容器级设置 runAsNonRoot 和 seccompProfile",
+			`{"containers": [{"name": "app", "securityContext": {"runAsNonRoot": true, "seccompProfile": {"type": "Localhost", "localhostProfile": "p.json"},
+				"allowPrivilegeEscalation": false, "capabilities": {"drop": ["ALL"]}}}]}`,
+			pssRestricted, nil,
+		},
+		{
+			"Pod 级 runAsNonRoot: false 不能被容器覆盖",
+			`{"securityContext": {"runAsNonRoot": false, "seccompProfile": {"type": "RuntimeDefault"}}, "containers": [
+				{"name": "app", "securityContext": {"runAsNonRoot": true, "allowPrivilegeEscalation": false, "capabilities": {"drop": ["ALL"]}}}]}`,
+			pssBaseline, []string{"restricted/Running as Non-root"},
+		},
+		{
+			"容器级 seccompProfile Unconfined 覆盖 Pod 级",
+			`{"securityContext": ` + pssTestPodContext + `, "containers": [
+				{"name": "app", "securityContext": {"seccompProfile": {"type": "Unconfined"}, "allowPrivilegeEscalation": false, "capabilities": {"drop": ["ALL"]}}}]}`,
+			pssPrivileged, []string{"baseline/Seccomp", "restricted/Seccomp"},
+		},
+		{
+			"未设置 seccompProfile",
+			`{"securityContext": {"runAsNonRoot": true}, "containers": [` + pssTestContainer + `]}`,
+			pssBaseline, []string{"restricted/Seccomp"},
+		},
+		{
+			"init 容器同样检查",
+			`{"securityContext": ` + pssTestPodContext + `, "initContainers": [{"name": "init", "securityContext": {"privileged": true}}],
+				"containers": [` + pssTestContainer + `]}`,
+			pssPrivileged, []string{"baseline/Privileged Containers", "restricted/Privilege Escalation", "restricted/Capabilities"},
+		},
+		{
+			"临时容器继承 Pod 级设置",
+			`{"securityContext": ` + pssTestPodContext + `, "containers": [` + pssTestContainer + `],
+				"ephemeralContainers": [{"name": "debug", "image": "busybox"}]}`,
+			pssBaseline, []string{"restricted/Privilege Escalation", "restricted/Capabilities"},
+		},
+		{
+			"特权临时容器",
+			`{"securityContext": ` + pssTestPodContext + `, "containers": [` + pssTestContainer + `],
+				"ephemeralContainers": [{"name": "debug", "securityContext": {"privileged": true, "allowPrivilegeEscalation": false, "capabilities": {"drop": ["ALL"]}}}]}`,
+			pssPrivileged, []string{"baseline/Privileged Containers"},
+		},
+		{
+			"hostPort: 0 等同于未设置",
+			`{"securityContext": ` + pssTestPodContext + `, "containers": [
+				{"name": "app", "ports": [{"containerPort": 80, "hostPort": 0}], "securityContext": {"allowPrivilegeEscalation": false, "capabilities": {"drop": ["ALL"]}}}]}`,
+			pssRestricted, nil,
+		},
+		{
+			"hostPort",
+			`{"securityContext": ` + pssTestPodContext + `, "containers": [
+				{"name": "app", "ports": [{"containerPort": 80, "hostPort": 8080}], "securityContext": {"allowPrivilegeEscalation": false, "capabilities": {"drop": ["ALL"]}}}]}`,
+			pssPrivileged, []string{"baseline/Host Ports"},
+		},
+		{
+			"未做任何加固",
+			`{"containers": [{"name": "app"}]}`,
+			pssBaseline, []string{"restricted/Privilege Escalation", "restricted/Running as Non-root", "restricted/Seccomp", "restricted/Capabilities"},
+		},
+	}
+	for _, tt := range tests {
+		info := ResourceInfo{Kind: "Pod", Namespace: "default", Name: "web", Parsed: map[string]interface{}{
+			"spec": testObject(t, tt.spec),
+		}}
+		result, ok := evaluatePodSecurity(info, langZH)
+		if !ok {
+			t.Errorf("%s: 未评估", tt.name)
+			continue
+		}
+		var got []string
+		for _, v := range result.Violations {
+			got = append(got, v.Level+"/"+v.Check)
+		}
+		if result.Level != tt.level || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: 级别 %s 未通过 %v, 期望 %s %v", tt.name, result.Level, got, tt.level, tt.want)
+		}
+	}
+}
+
+func TestEvaluatePodSecurityWorkloads(t *testing.T) {
+	podSpec := `{"securityContext": ` + pssTestPodContext + `, "containers": [` + pssTestContainer + `]}`
+	tests := []struct {
+		kind   string
+		parsed string
+		ok     bool
+	}{
+		{"Deployment", `{"spec": {"template": {"spec": ` + podSpec + `}}}`, true},
+		{"CronJob", `{"spec": {"jobTemplate": {"spec": {"template": {"spec": ` + podSpec + `}}}}}`, true},
+		{"Service", `{"spec": {"ports": [{"port": 80}]}}`, false},
+	}
+	for _, tt := range tests {
+		info := ResourceInfo{Kind: tt.kind, Name: "web", Parsed: testObject(t, tt.parsed).(map[string]interface{})}
+		result, ok := evaluatePodSecurity(info, langZH)
+		if ok != tt.ok {
+			t.Errorf("%s: 是否评估 = %v, 期望 %v", tt.kind, ok, tt.ok)
+			continue
+		}
+		if ok && (result.Level != pssRestricted || len(result.Violations) != 0) {
+			t.Errorf("%s: 级别 %s 未通过 %v, 期望 restricted", tt.kind, result.Level, result.Violations)
+		}
+	}
+}
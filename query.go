@@ -0,0 +1,116 @@
+package main
+
+import (
+	"net/http"
+)
+
+// 查询语言
+const (
+	queryLangJSONPath = "jsonpath"
+	queryLangJQ       = "jq"
+)
+
+// 查询结果的一行，Index 指向资源列表中的位置，便于打开详情
+type QueryRow struct {
+	Index     int         `json:"index"`
+	Kind      string      `json:"kind"`
+	Namespace string      `json:"namespace"`
+	Name      string      `json:"name"`
+	Value     interface{} `json:"value"`
+}
+
+// 查询结果
+type QueryResult struct {
+	Language string     `json:"language"`
+	Query    string     `json:"query"`
+	Count    int        `json:"count"`
+	Rows     []QueryRow `json:"rows"`
+	// 求值出错的资源数及第一个错误，便于排查表达式
+	Errors     int    `json:"errors,omitempty"`
+	FirstError string `json:"firstError,omitempty"`
+}
+
+// 编译查询，返回对单个对象求值的函数
+func compileQuery(lang, query string) (func(obj interface{}) ([]interface{}, error), error) {
+	switch lang {
+	case "", queryLangJSONPath:
+		tmpl, err := compileJSONPathTemplate(query)
+		if err != nil {
+			return nil, err
+		}
+		return func(obj interface{}) ([]interface{}, error) {
+			return tmpl.Evaluate(obj), nil
+		}, nil
+	case queryLangJQ:
+		node, err := compileJQ(query)
+		if err != nil {
+			return nil, err
+		}
+		return node.eval, nil
+	default:
+		return nil, errorf("不支持的查询语言: %s（可选 jsonpath、jq）", lang)
+	}
+}
+
+// 在指定资源上执行查询，null 结果不输出；locale 为错误信息的界面语言
+func runQuery(infos []ResourceInfo, indices []int, lang, query, locale string) (QueryResult, error) {
+	if lang == "" {
+		lang = queryLangJSONPath
+	}
+	eval, err := compileQuery(lang, query)
+	if err != nil {
+		return QueryResult{}, err
+	}
+
+	result := QueryResult{Language: lang, Query: query, Rows: []QueryRow{}}
+	for _, index := range indices {
+		info := infos[index]
+		values, err := eval(map[string]interface{}(info.Parsed))
+		if err != nil {
+			result.Errors++
+			if result.FirstError == "" {
+				result.FirstError = info.Kind + "/" + info.Name + ": " + localizeError(locale, err)
+			}
+			continue
+		}
+		for _, v := range values {
+			if v == nil {
+				continue
+			}
+			result.Rows = append(result.Rows, QueryRow{
+				Index:     index,
+				Kind:      info.Kind,
+				Namespace: info.Namespace,
+				Name:      info.Name,
+				Value:     v,
+			})
+		}
+	}
+	result.Count = len(result.Rows)
+	return result, nil
+}
+
+// GET /api/v1/query?lang=jsonpath|jq&q=...，可与 /api/v1/resources 的过滤参数组合使用
+func apiQueryHandler(pageFor func(*http.Request) PageData) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("q")
+		if query == "" {
+			writeAPIError(w, r, http.StatusBadRequest, "缺少查询参数 q")
+			return
+		}
+
+		infos := pageFor(r).Resources
+		indices, err := filterResources(infos, r)
+		if err != nil {
+			writeAPIError(w, r, http.StatusBadRequest, "%v", err)
+			return
+		}
+
+		result, err := runQuery(infos, indices, r.URL.Query().Get("lang"), query, requestLanguage(r))
+		if err != nil {
+			writeAPIError(w, r, http.StatusBadRequest, "%v", err)
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
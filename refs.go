@@ -0,0 +1,254 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// 资源之间的一条引用；Index 为目标资源在列表中的位置，-1 表示未加载
+type ResourceRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Via       string `json:"via"` // 引用位置，如 env DB_PASS、volume data
+	Index     int    `json:"index"`
+	Optional  bool   `json:"optional,omitempty"`
+}
+
+// 内置的 PriorityClass，不会出现在 kubectl get 的结果之外也始终存在
+var builtinPriorityClasses = map[string]bool{
+	"system-cluster-critical": true,
+	"system-node-critical":    true,
+}
+
+// 不视为孤立资源的 ConfigMap 和 Secret 类型，由集群组件自动创建或使用
+var (
+	implicitConfigMaps  = map[string]bool{"kube-root-ca.crt": true}
+	implicitSecretTypes = map[string]bool{
+		"kubernetes.io/service-account-token": true,
+		"helm.sh/release.v1":                  true,
+		"bootstrap.kubernetes.io/token":       true,
+	}
+)
+
+func resourceKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// 列出资源对 ConfigMap、Secret、PVC、ServiceAccount 和 PriorityClass 的引用（尚未解析 Index）
+func outgoingRefs(info ResourceInfo, lang string) []ResourceRef {
+	var refs []ResourceRef
+	add := func(kind, name, via string, optional bool) {
+		if name == "" {
+			return
+		}
+		namespace := info.Namespace
+		if kind == "PriorityClass" {
+			namespace = ""
+		}
+		refs = append(refs, ResourceRef{Kind: kind, Namespace: namespace, Name: name, Via: via, Optional: optional})
+	}
+
+	if spec := podSpec(info); spec != nil {
+		sa := nestedString(spec, "serviceAccountName")
+		if sa == "" {
+			sa = nestedString(spec, "serviceAccount")
+		}
+		add("ServiceAccount", sa, "serviceAccountName", false)
+		add("PriorityClass", nestedString(spec, "priorityClassName"), "priorityClassName", false)
+		for _, item := range nestedSlice(spec, "imagePullSecrets") {
+			if s, ok := item.(map[string]interface{}); ok {
+				add("Secret", nestedString(s, "name"), "imagePullSecrets", false)
+			}
+		}
+
+		for _, item := range nestedSlice(spec, "volumes") {
+			v, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			via := "volume " + nestedString(v, "name")
+			if cm := nestedMap(v, "configMap"); cm != nil {
+				add("ConfigMap", nestedString(cm, "name"), via, cm["optional"] == true)
+			}
+			if s := nestedMap(v, "secret"); s != nil {
+				add("Secret", nestedString(s, "secretName"), via, s["optional"] == true)
+			}
+			if pvc := nestedMap(v, "persistentVolumeClaim"); pvc != nil {
+				add("PersistentVolumeClaim", nestedString(pvc, "claimName"), via, false)
+			}
+			for _, src := range nestedSlice(v, "projected", "sources") {
+				s, ok := src.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if cm := nestedMap(s, "configMap"); cm != nil {
+					add("ConfigMap", nestedString(cm, "name"), via, cm["optional"] == true)
+				}
+				if secret := nestedMap(s, "secret"); secret != nil {
+					add("Secret", nestedString(secret, "name"), via, secret["optional"] == true)
+				}
+			}
+		}
+
+		for _, c := range podContainers(spec) {
+			for _, item := range nestedSlice(c.Spec, "env") {
+				e, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				via := tr(lang, "容器 %s env %s", c.Name, nestedString(e, "name"))
+				if ref := nestedMap(e, "valueFrom", "configMapKeyRef"); ref != nil {
+					add("ConfigMap", nestedString(ref, "name"), via, ref["optional"] == true)
+				}
+				if ref := nestedMap(e, "valueFrom", "secretKeyRef"); ref != nil {
+					add("Secret", nestedString(ref, "name"), via, ref["optional"] == true)
+				}
+			}
+			for _, item := range nestedSlice(c.Spec, "envFrom") {
+				e, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				via := tr(lang, "容器 %s envFrom", c.Name)
+				if ref := nestedMap(e, "configMapRef"); ref != nil {
+					add("ConfigMap", nestedString(ref, "name"), via, ref["optional"] == true)
+				}
+				if ref := nestedMap(e, "secretRef"); ref != nil {
+					add("Secret", nestedString(ref, "name"), via, ref["optional"] == true)
+				}
+			}
+		}
+	}
+
+	switch info.Kind {
+	case "ServiceAccount":
+		for _, field := range []string{"secrets", "imagePullSecrets"} {
+			for _, item := range nestedSlice(info.Parsed, field) {
+				if s, ok := item.(map[string]interface{}); ok {
+					add("Secret", nestedString(s, "name"), field, false)
+				}
+			}
+		}
+	case "Ingress":
+		for _, item := range nestedSlice(info.Parsed, "spec", "tls") {
+			if t, ok := item.(map[string]interface{}); ok {
+				add("Secret", nestedString(t, "secretName"), "tls "+strings.Join(stringSlice(nestedSlice(t, "hosts")), ","), false)
+			}
+		}
+	case "Gateway":
+		for _, item := range nestedSlice(info.Parsed, "spec", "listeners") {
+			l, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, ref := range nestedSlice(l, "tls", "certificateRefs") {
+				r, ok := ref.(map[string]interface{})
+				// 跨命名空间的证书引用需要 ReferenceGrant，这里只解析同命名空间的 Secret
+				if !ok || nestedString(r, "namespace") != "" && nestedString(r, "namespace") != info.Namespace {
+					continue
+				}
+				if kind := nestedString(r, "kind"); kind == "" || kind == "Secret" {
+					add("Secret", nestedString(r, "name"), "listener "+nestedString(l, "name"), false)
+				}
+			}
+		}
+	}
+	return refs
+}
+
+func stringSlice(items []interface{}) []string {
+	var out []string
+	for _, item := range items {
+		out = append(out, stringValue(item))
+	}
+	return out
+}
+
+// 引用报告中的一行
+type ReferenceRow struct {
+	ResourceRef
+	SourceIndex     int
+	SourceKind      string
+	SourceNamespace string
+	SourceName      string
+}
+
+type ReferenceReport struct {
+	Dangling    []ReferenceRow
+	Orphans     []int    // 未被任何资源引用的 ConfigMap / Secret
+	LoadedKinds []string // 已加载、可判断引用是否悬空的目标类型
+	Resolved    int
+}
+
+// 解析资源间引用，填充每个资源的 Uses / UsedBy，并找出悬空引用和孤立资源。
+// 只有目标类型已被加载时才判断引用是否悬空，避免只加载 Pod 时全部报告为缺失
+func buildReferenceReport(infos []ResourceInfo, lang string) ReferenceReport {
+	var report ReferenceReport
+	index := make(map[string]int, len(infos))
+	loaded := make(map[string]bool)
+	workloadNamespaces := make(map[string]bool)
+	for i, info := range infos {
+		index[resourceKey(info.Kind, info.Namespace, info.Name)] = i
+		loaded[info.Kind] = true
+		if podSpec(info) != nil {
+			workloadNamespaces[info.Namespace] = true
+		}
+	}
+	for _, kind := range []string{"ConfigMap", "Secret", "PersistentVolumeClaim", "ServiceAccount", "PriorityClass"} {
+		if loaded[kind] {
+			report.LoadedKinds = append(report.LoadedKinds, kind)
+		}
+	}
+
+	referenced := make(map[int]bool)
+	for i := range infos {
+		info := &infos[i]
+		for _, ref := range outgoingRefs(*info, lang) {
+			target, ok := index[resourceKey(ref.Kind, ref.Namespace, ref.Name)]
+			if !ok {
+				ref.Index = -1
+				missing := loaded[ref.Kind] && !ref.Optional &&
+					!(ref.Kind == "PriorityClass" && builtinPriorityClasses[ref.Name])
+				if missing {
+					report.Dangling = append(report.Dangling, ReferenceRow{
+						ResourceRef:     ref,
+						SourceIndex:     i,
+						SourceKind:      info.Kind,
+						SourceNamespace: info.Namespace,
+						SourceName:      info.Name,
+					})
+				}
+				info.Uses = append(info.Uses, ref)
+				continue
+			}
+			ref.Index = target
+			info.Uses = append(info.Uses, ref)
+			report.Resolved++
+			referenced[target] = true
+			infos[target].UsedBy = append(infos[target].UsedBy, ResourceRef{
+				Kind:      info.Kind,
+				Namespace: info.Namespace,
+				Name:      info.Name,
+				Via:       ref.Via,
+				Index:     i,
+			})
+		}
+	}
+
+	// 只在加载了工作负载的命名空间中判断孤立资源
+	for i, info := range infos {
+		if referenced[i] || !workloadNamespaces[info.Namespace] {
+			continue
+		}
+		switch {
+		case info.Kind == "ConfigMap" && !implicitConfigMaps[info.Name],
+			info.Kind == "Secret" && !implicitSecretTypes[nestedString(info.Parsed, "type")]:
+			report.Orphans = append(report.Orphans, i)
+		}
+	}
+	sort.SliceStable(report.Dangling, func(i, j int) bool {
+		return report.Dangling[i].Kind < report.Dangling[j].Kind
+	})
+	return report
+}
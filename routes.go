@@ -0,0 +1,440 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 路由的一个后端
+type RouteBackend struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Port      string
+	Weight    string
+	Index     int    // 后端 Service 在资源列表中的位置，-1 表示未加载
+	Problem   string // Service 或端口不存在
+}
+
+// 路由表中的一行：一个主机名 + 路径（或 gRPC 方法）及其后端
+type RouteEntry struct {
+	Index     int
+	Kind      string
+	Namespace string
+	Name      string
+	Parent    string // IngressClass 或所属 Gateway
+	Host      string
+	Path      string
+	PathType  string
+	Backends  []RouteBackend
+	TLS       []string // 使用的 TLS Secret
+	Conflict  bool
+}
+
+// Gateway 的一个监听器
+type GatewayListener struct {
+	Name      string
+	Hostname  string
+	Port      string
+	Protocol  string
+	TLSSecret []string
+}
+
+type GatewaySummary struct {
+	Index     int
+	Namespace string
+	Name      string
+	Class     string
+	Listeners []GatewayListener
+	Routes    int
+}
+
+// 多个对象声明了相同的主机名和路径
+type RouteConflict struct {
+	Parent         string
+	Host           string
+	Path           string
+	PathType       string
+	Entries        []RouteEntry
+	CrossNamespace bool
+}
+
+type RouteMap struct {
+	Routes          []RouteEntry
+	Gateways        []GatewaySummary
+	Conflicts       []RouteConflict
+	ServicesLoaded  bool
+	BackendProblems int
+}
+
+func (m RouteMap) Present() bool {
+	return len(m.Routes) > 0 || len(m.Gateways) > 0
+}
+
+// 主机名是否匹配，支持 *.example.com 形式的通配符；空主机名匹配所有
+func hostMatches(pattern, host string) bool {
+	if pattern == "" || pattern == "*" || pattern == host {
+		return true
+	}
+	if strings.HasPrefix(pattern, "*.") {
+		suffix := pattern[1:]
+		return strings.HasSuffix(host, suffix) && strings.Count(host, ".") == strings.Count(pattern, ".")
+	}
+	return false
+}
+
+type routeBuilder struct {
+	infos          []ResourceInfo
+	index          map[string]int
+	servicesLoaded bool
+	lang           string // 后端问题描述使用的语言
+}
+
+// 解析后端 Service，检查其是否存在以及端口是否已声明
+func (b *routeBuilder) backend(kind, namespace, name, port, weight string) RouteBackend {
+	if kind == "" {
+		kind = "Service"
+	}
+	backend := RouteBackend{Kind: kind, Namespace: namespace, Name: name, Port: port, Weight: weight, Index: -1}
+	if kind != "Service" {
+		return backend
+	}
+	i, ok := b.index[resourceKey("Service", namespace, name)]
+	if !ok {
+		if b.servicesLoaded {
+			backend.Problem = tr(b.lang, "Service 不存在")
+		}
+		return backend
+	}
+	backend.Index = i
+	if port == "" {
+		return backend
+	}
+	for _, item := range nestedSlice(b.infos[i].Parsed, "spec", "ports") {
+		if p, ok := item.(map[string]interface{}); ok && (nestedString(p, "port") == port || nestedString(p, "name") == port) {
+			return backend
+		}
+	}
+	backend.Problem = tr(b.lang, "Service 没有端口 %s", port)
+	return backend
+}
+
+// 兼容 networking.k8s.io/v1 和旧版 (serviceName/servicePort) 的 Ingress 后端
+func (b *routeBuilder) ingressBackend(namespace string, backend map[string]interface{}) RouteBackend {
+	if svc := nestedMap(backend, "service"); svc != nil {
+		port := nestedString(svc, "port", "number")
+		if port == "" {
+			port = nestedString(svc, "port", "name")
+		}
+		return b.backend("Service", namespace, nestedString(svc, "name"), port, "")
+	}
+	if name := nestedString(backend, "serviceName"); name != "" {
+		return b.backend("Service", namespace, name, nestedString(backend, "servicePort"), "")
+	}
+	if res := nestedMap(backend, "resource"); res != nil {
+		return b.backend(nestedString(res, "kind"), namespace, nestedString(res, "name"), "", "")
+	}
+	return RouteBackend{Index: -1}
+}
+
+func (b *routeBuilder) ingressRoutes(i int, info ResourceInfo) []RouteEntry {
+	class := nestedString(info.Parsed, "spec", "ingressClassName")
+	if class == "" {
+		class = resourceAnnotations(info)["kubernetes.io/ingress.class"]
+	}
+	type tlsEntry struct {
+		hosts  []string
+		secret string
+	}
+	var tls []tlsEntry
+	for _, item := range nestedSlice(info.Parsed, "spec", "tls") {
+		if t, ok := item.(map[string]interface{}); ok {
+			tls = append(tls, tlsEntry{stringSlice(nestedSlice(t, "hosts")), nestedString(t, "secretName")})
+		}
+	}
+	tlsFor := func(host string) []string {
+		var secrets []string
+		for _, t := range tls {
+			for _, h := range t.hosts {
+				if hostMatches(h, host) && t.secret != "" && !containsString(secrets, t.secret) {
+					secrets = append(secrets, t.secret)
+				}
+			}
+		}
+		return secrets
+	}
+	entry := func(host, path, pathType string, backend RouteBackend) RouteEntry {
+		if host == "" {
+			host = "*"
+		}
+		return RouteEntry{
+			Index: i, Kind: info.Kind, Namespace: info.Namespace, Name: info.Name, Parent: class,
+			Host: host, Path: path, PathType: pathType, Backends: []RouteBackend{backend}, TLS: tlsFor(host),
+		}
+	}
+
+	var routes []RouteEntry
+	defaultBackend := nestedMap(info.Parsed, "spec", "defaultBackend")
+	if defaultBackend == nil {
+		defaultBackend = nestedMap(info.Parsed, "spec", "backend")
+	}
+	if defaultBackend != nil {
+		routes = append(routes, entry("", tr(b.lang, "(默认后端)"), "", b.ingressBackend(info.Namespace, defaultBackend)))
+	}
+	for _, item := range nestedSlice(info.Parsed, "spec", "rules") {
+		rule, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		host := nestedString(rule, "host")
+		for _, p := range nestedSlice(rule, "http", "paths") {
+			path, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			value := nestedString(path, "path")
+			if value == "" {
+				value = "/"
+			}
+			routes = append(routes, entry(host, value, nestedString(path, "pathType"), b.ingressBackend(info.Namespace, nestedMap(path, "backend"))))
+		}
+	}
+	return routes
+}
+
+func gatewaySummary(i int, info ResourceInfo) GatewaySummary {
+	gw := GatewaySummary{
+		Index:     i,
+		Namespace: info.Namespace,
+		Name:      info.Name,
+		Class:     nestedString(info.Parsed, "spec", "gatewayClassName"),
+	}
+	for _, item := range nestedSlice(info.Parsed, "spec", "listeners") {
+		l, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		listener := GatewayListener{
+			Name:     nestedString(l, "name"),
+			Hostname: nestedString(l, "hostname"),
+			Port:     nestedString(l, "port"),
+			Protocol: nestedString(l, "protocol"),
+		}
+		for _, ref := range nestedSlice(l, "tls", "certificateRefs") {
+			if r, ok := ref.(map[string]interface{}); ok {
+				if kind := nestedString(r, "kind"); kind == "" || kind == "Secret" {
+					listener.TLSSecret = append(listener.TLSSecret, nestedString(r, "name"))
+				}
+			}
+		}
+		gw.Listeners = append(gw.Listeners, listener)
+	}
+	return gw
+}
+
+// HTTPRoute / GRPCRoute 的路由；主机名未指定时继承父 Gateway 监听器的主机名
+func (b *routeBuilder) gatewayRoutes(i int, info ResourceInfo, gateways map[string]*GatewaySummary) []RouteEntry {
+	type parent struct {
+		name      string
+		gateway   *GatewaySummary
+		listeners []GatewayListener
+	}
+	var parents []parent
+	for _, item := range nestedSlice(info.Parsed, "spec", "parentRefs") {
+		ref, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		namespace := nestedString(ref, "namespace")
+		if namespace == "" {
+			namespace = info.Namespace
+		}
+		p := parent{name: namespace + "/" + nestedString(ref, "name")}
+		if section := nestedString(ref, "sectionName"); section != "" {
+			p.name += "#" + section
+		}
+		if gw := gateways[resourceKey("Gateway", namespace, nestedString(ref, "name"))]; gw != nil {
+			p.gateway = gw
+			section := nestedString(ref, "sectionName")
+			for _, l := range gw.Listeners {
+				if section == "" || l.Name == section {
+					p.listeners = append(p.listeners, l)
+				}
+			}
+		}
+		parents = append(parents, p)
+	}
+	if len(parents) == 0 {
+		parents = append(parents, parent{name: "-"})
+	}
+
+	hostnames := stringSlice(nestedSlice(info.Parsed, "spec", "hostnames"))
+	var routes []RouteEntry
+	for _, p := range parents {
+		hosts := hostnames
+		if len(hosts) == 0 {
+			for _, l := range p.listeners {
+				if !containsString(hosts, l.Hostname) {
+					hosts = append(hosts, l.Hostname)
+				}
+			}
+		}
+		if len(hosts) == 0 {
+			hosts = []string{"*"}
+		}
+		if p.gateway != nil {
+			p.gateway.Routes++
+		}
+
+		for _, item := range nestedSlice(info.Parsed, "spec", "rules") {
+			rule, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			var backends []RouteBackend
+			for _, ref := range nestedSlice(rule, "backendRefs") {
+				r, ok := ref.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				namespace := nestedString(r, "namespace")
+				if namespace == "" {
+					namespace = info.Namespace
+				}
+				backends = append(backends, b.backend(nestedString(r, "kind"), namespace, nestedString(r, "name"), nestedString(r, "port"), nestedString(r, "weight")))
+			}
+
+			var paths [][2]string // {path, type}
+			for _, m := range nestedSlice(rule, "matches") {
+				match, ok := m.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if method := nestedMap(match, "method"); method != nil {
+					paths = append(paths, [2]string{"/" + nestedString(method, "service") + "/" + nestedString(method, "method"), nestedString(method, "type")})
+				} else if path := nestedMap(match, "path"); path != nil {
+					paths = append(paths, [2]string{nestedString(path, "value"), nestedString(path, "type")})
+				} else {
+					paths = append(paths, [2]string{"/", "PathPrefix"})
+				}
+			}
+			if len(paths) == 0 {
+				paths = append(paths, [2]string{"/", "PathPrefix"})
+			}
+
+			for _, host := range hosts {
+				if host == "" {
+					host = "*"
+				}
+				var tls []string
+				for _, l := range p.listeners {
+					if hostMatches(l.Hostname, host) {
+						for _, secret := range l.TLSSecret {
+							if !containsString(tls, secret) {
+								tls = append(tls, secret)
+							}
+						}
+					}
+				}
+				for _, path := range paths {
+					routes = append(routes, RouteEntry{
+						Index: i, Kind: info.Kind, Namespace: info.Namespace, Name: info.Name, Parent: p.name,
+						Host: host, Path: path[0], PathType: path[1], Backends: backends, TLS: tls,
+					})
+				}
+			}
+		}
+	}
+	return routes
+}
+
+// 汇总 Ingress 与 Gateway API 路由，并检测不同对象之间重复的主机名 + 路径；
+// 只有挂在同一 IngressClass 或同一 Gateway 监听器下、路径匹配类型也相同的路由才会互相覆盖
+func buildRouteMap(infos []ResourceInfo, lang string) RouteMap {
+	var report RouteMap
+	b := &routeBuilder{infos: infos, index: make(map[string]int, len(infos)), lang: lang}
+	for i, info := range infos {
+		b.index[resourceKey(info.Kind, info.Namespace, info.Name)] = i
+		if info.Kind == "Service" {
+			b.servicesLoaded = true
+		}
+	}
+	report.ServicesLoaded = b.servicesLoaded
+
+	gateways := make(map[string]*GatewaySummary)
+	for i, info := range infos {
+		if info.Kind == "Gateway" && strings.HasPrefix(info.APIVersion, "gateway.networking.k8s.io/") {
+			report.Gateways = append(report.Gateways, gatewaySummary(i, info))
+		}
+	}
+	for i := range report.Gateways {
+		gw := &report.Gateways[i]
+		gateways[resourceKey("Gateway", gw.Namespace, gw.Name)] = gw
+	}
+
+	for i, info := range infos {
+		switch {
+		case info.Kind == "Ingress":
+			report.Routes = append(report.Routes, b.ingressRoutes(i, info)...)
+		case (info.Kind == "HTTPRoute" || info.Kind == "GRPCRoute") && strings.HasPrefix(info.APIVersion, "gateway.networking.k8s.io/"):
+			report.Routes = append(report.Routes, b.gatewayRoutes(i, info, gateways)...)
+		}
+	}
+
+	// 同一对象内的重复（如多个父 Gateway）不算冲突
+	groups := make(map[string][]int)
+	var keys []string
+	for i, r := range report.Routes {
+		key := r.Parent + " " + r.Host + " " + r.PathType + " " + r.Path
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+	for _, key := range keys {
+		members := groups[key]
+		objects := make(map[int]bool)
+		namespaces := make(map[string]bool)
+		for _, m := range members {
+			objects[report.Routes[m].Index] = true
+			namespaces[report.Routes[m].Namespace] = true
+		}
+		if len(objects) < 2 {
+			continue
+		}
+		conflict := RouteConflict{
+			Parent:         report.Routes[members[0]].Parent,
+			Host:           report.Routes[members[0]].Host,
+			Path:           report.Routes[members[0]].Path,
+			PathType:       report.Routes[members[0]].PathType,
+			CrossNamespace: len(namespaces) > 1,
+		}
+		for _, m := range members {
+			report.Routes[m].Conflict = true
+			conflict.Entries = append(conflict.Entries, report.Routes[m])
+		}
+		report.Conflicts = append(report.Conflicts, conflict)
+	}
+
+	for _, r := range report.Routes {
+		for _, backend := range r.Backends {
+			if backend.Problem != "" {
+				report.BackendProblems++
+			}
+		}
+	}
+	sort.SliceStable(report.Routes, func(i, j int) bool {
+		a, b := report.Routes[i], report.Routes[j]
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		return a.Path < b.Path
+	})
+	return report
+}
+
+// 路由表中对象的显示名称
+func (r RouteEntry) Source() string {
+	return fmt.Sprintf("%s %s/%s", r.Kind, r.Namespace, r.Name)
+}
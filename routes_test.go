@@ -0,0 +1,80 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testIngress(t *testing.T, namespace, name, class, path, pathType string) ResourceInfo {
+	t.Helper()
+	obj := testObject(t, `{
+  "apiVersion": "networking.k8s.io/v1",
+  "kind": "Ingress",
+  "metadata": {"name": "`+name+`", "namespace": "`+namespace+`"},
+  "spec": {
+    "ingressClassName": "`+class+`",
+    "rules": [{"host": "example.com", "http": {"paths": [
+      {"path": "`+path+`", "pathType": "`+pathType+`", "backend": {"service": {"name": "web", "port": {"number": 80}}}}
+    ]}}]
+  }
+}`).(map[string]interface{})
+	return ResourceInfo{APIVersion: "networking.k8s.io/v1", Kind: "Ingress", Namespace: namespace, Name: name, Parsed: obj}
+}
+
+func testHTTPRoute(t *testing.T, namespace, name, parent, path string) ResourceInfo {
+	t.Helper()
+	obj := testObject(t, `{
+  "apiVersion": "gateway.networking.k8s.io/v1",
+  "kind": "HTTPRoute",
+  "metadata": {"name": "`+name+`", "namespace": "`+namespace+`"},
+  "spec": {
+    "parentRefs": [{"name": "`+parent+`"}],
+    "hostnames": ["example.com"],
+    "rules": [{"matches": [{"path": {"type": "PathPrefix", "value": "`+path+`"}}], "backendRefs": [{"name": "web", "port": 80}]}]
+  }
+}`).(map[string]interface{})
+	return ResourceInfo{APIVersion: "gateway.networking.k8s.io/v1", Kind: "HTTPRoute", Namespace: namespace, Name: name, Parsed: obj}
+}
+
+func TestRouteConflicts(t *testing.T) {
+	tests := []struct {
+		name  string
+		infos []ResourceInfo
+		want  []string // 冲突的 IngressClass / Gateway
+	}{
+		{"同一 IngressClass", []ResourceInfo{
+			testIngress(t, "a", "one", "nginx", "/", "Prefix"),
+			testIngress(t, "b", "two", "nginx", "/", "Prefix"),
+		}, []string{"nginx"}},
+		{"不同 IngressClass", []ResourceInfo{
+			testIngress(t, "a", "one", "nginx", "/", "Prefix"),
+			testIngress(t, "a", "two", "traefik", "/", "Prefix"),
+		}, nil},
+		{"不同路径匹配类型", []ResourceInfo{
+			testIngress(t, "a", "one", "nginx", "/api", "Prefix"),
+			testIngress(t, "a", "two", "nginx", "/api", "Exact"),
+		}, nil},
+		{"同一 Gateway", []ResourceInfo{
+			testHTTPRoute(t, "a", "one", "gw", "/"),
+			testHTTPRoute(t, "a", "two", "gw", "/"),
+		}, []string{"a/gw"}},
+		{"不同 Gateway", []ResourceInfo{
+			testHTTPRoute(t, "a", "one", "gw", "/"),
+			testHTTPRoute(t, "a", "two", "internal", "/"),
+		}, nil},
+		{"Ingress 与 HTTPRoute", []ResourceInfo{
+			testIngress(t, "a", "one", "nginx", "/", "Prefix"),
+			testHTTPRoute(t, "a", "two", "nginx", "/"),
+		}, nil},
+	}
+	for _, tt := range tests {
+		report := buildRouteMap(tt.infos, langZH)
+		var got []string
+		for _, c := range report.Conflicts {
+			got = append(got, c.Parent)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: 冲突 = %v, 期望 %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,463 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 标签选择器运算符，与 k8s.io/apimachinery/pkg/selection 保持一致
+const (
+	selectorEquals       = "="
+	selectorDoubleEquals = "=="
+	selectorNotEquals    = "!="
+	selectorIn           = "in"
+	selectorNotIn        = "notin"
+	selectorExists       = "exists"
+	selectorDoesNotExist = "!"
+	selectorGreaterThan  = "gt"
+	selectorLessThan     = "lt"
+)
+
+// 单个标签选择器条件
+type LabelRequirement struct {
+	Key      string
+	Operator string
+	Values   []string
+}
+
+// 标签选择器，所有条件为与关系；空选择器匹配所有资源
+type LabelSelector []LabelRequirement
+
+var (
+	labelValuePattern    = regexp.MustCompile(`^(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?$`)
+	labelNamePattern     = regexp.MustCompile(`^([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]$`)
+	dnsSubdomainPattern  = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	selectorSpecialChars = "=!(),<> \t"
+)
+
+// 校验标签键：可选的 DNS 子域前缀 + "/" + 不超过 63 个字符的名称
+func validateLabelKey(key string) error {
+	name := key
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		prefix := key[:i]
+		name = key[i+1:]
+		if prefix == "" || len(prefix) > 253 || !dnsSubdomainPattern.MatchString(prefix) {
+			return errorf("无效的标签键 %q: 前缀必须是合法的 DNS 子域名", key)
+		}
+	}
+	if name == "" || len(name) > 63 || !labelNamePattern.MatchString(name) {
+		return errorf("无效的标签键 %q: 名称必须由字母数字、'-'、'_'、'.' 组成，以字母数字开头和结尾，且不超过 63 个字符", key)
+	}
+	return nil
+}
+
+// 校验标签值：可以为空，否则规则与名称相同
+func validateLabelValue(value string) error {
+	if len(value) > 63 || !labelValuePattern.MatchString(value) {
+		return errorf("无效的标签值 %q: 必须由字母数字、'-'、'_'、'.' 组成，以字母数字开头和结尾，且不超过 63 个字符", value)
+	}
+	return nil
+}
+
+// 标签选择器词法分析器
+type selectorLexer struct {
+	s   string
+	pos int
+}
+
+func (l *selectorLexer) skipSpaces() {
+	for l.pos < len(l.s) && (l.s[l.pos] == ' ' || l.s[l.pos] == '\t') {
+		l.pos++
+	}
+}
+
+func (l *selectorLexer) eof() bool {
+	l.skipSpaces()
+	return l.pos >= len(l.s)
+}
+
+// 读取标识符（键或值），遇到特殊字符停止
+func (l *selectorLexer) readIdentifier() string {
+	l.skipSpaces()
+	start := l.pos
+	for l.pos < len(l.s) && !strings.ContainsRune(selectorSpecialChars, rune(l.s[l.pos])) {
+		l.pos++
+	}
+	return l.s[start:l.pos]
+}
+
+func (l *selectorLexer) consume(token string) bool {
+	l.skipSpaces()
+	if strings.HasPrefix(l.s[l.pos:], token) {
+		l.pos += len(token)
+		return true
+	}
+	return false
+}
+
+// 读取运算符关键字 in / notin，要求其后是空白或 (
+func (l *selectorLexer) consumeKeyword(word string) bool {
+	l.skipSpaces()
+	rest := l.s[l.pos:]
+	if !strings.HasPrefix(rest, word) {
+		return false
+	}
+	after := rest[len(word):]
+	if after != "" && after[0] != ' ' && after[0] != '\t' && after[0] != '(' {
+		return false
+	}
+	l.pos += len(word)
+	return true
+}
+
+// 解析标签选择器，语法与 kubectl -l 一致:
+//
+//	app=web,tier!=db,env in (prod,staging),track notin (canary),release,!legacy,replicas>2
+func ParseLabelSelector(input string) (LabelSelector, error) {
+	var selector LabelSelector
+	l := &selectorLexer{s: strings.TrimSpace(input)}
+	if l.eof() {
+		return selector, nil
+	}
+
+	for {
+		req, err := parseLabelRequirement(l)
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, req)
+
+		if l.eof() {
+			break
+		}
+		if !l.consume(",") {
+			return nil, errorf("标签选择器第 %d 个字符附近应为 ','", l.pos+1)
+		}
+		if l.eof() {
+			return nil, errorf("标签选择器以 ',' 结尾")
+		}
+	}
+
+	// 与 upstream 一致：按键排序，保证 String() 输出稳定
+	sort.SliceStable(selector, func(i, j int) bool { return selector[i].Key < selector[j].Key })
+	return selector, nil
+}
+
+func parseLabelRequirement(l *selectorLexer) (LabelRequirement, error) {
+	if l.consume("!") {
+		key := l.readIdentifier()
+		if err := validateLabelKey(key); err != nil {
+			return LabelRequirement{}, err
+		}
+		return LabelRequirement{Key: key, Operator: selectorDoesNotExist}, nil
+	}
+
+	key := l.readIdentifier()
+	if key == "" {
+		return LabelRequirement{}, errorf("标签选择器第 %d 个字符附近缺少键", l.pos+1)
+	}
+	if err := validateLabelKey(key); err != nil {
+		return LabelRequirement{}, err
+	}
+
+	if l.eof() || strings.HasPrefix(l.s[l.pos:], ",") {
+		return LabelRequirement{Key: key, Operator: selectorExists}, nil
+	}
+
+	var op string
+	switch {
+	case l.consume("=="):
+		op = selectorDoubleEquals
+	case l.consume("!="):
+		op = selectorNotEquals
+	case l.consume("="):
+		op = selectorEquals
+	case l.consume(">"):
+		op = selectorGreaterThan
+	case l.consume("<"):
+		op = selectorLessThan
+	case l.consumeKeyword("notin"):
+		op = selectorNotIn
+	case l.consumeKeyword("in"):
+		op = selectorIn
+	default:
+		return LabelRequirement{}, errorf("标签选择器第 %d 个字符附近应为运算符 (=, ==, !=, in, notin, >, <)", l.pos+1)
+	}
+
+	req := LabelRequirement{Key: key, Operator: op}
+	switch op {
+	case selectorIn, selectorNotIn:
+		if !l.consume("(") {
+			return LabelRequirement{}, errorf("%s 运算符后应为 '('", op)
+		}
+		if l.consume(")") {
+			return LabelRequirement{}, errorf("%s 运算符的值集合不能为空", op)
+		}
+		for {
+			value := l.readIdentifier()
+			if err := validateLabelValue(value); err != nil {
+				return LabelRequirement{}, err
+			}
+			req.Values = append(req.Values, value)
+			if l.consume(")") {
+				break
+			}
+			if !l.consume(",") {
+				return LabelRequirement{}, errorf("值集合中应为 ',' 或 ')'")
+			}
+		}
+		sort.Strings(req.Values)
+	case selectorGreaterThan, selectorLessThan:
+		value := l.readIdentifier()
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return LabelRequirement{}, errorf("%s 运算符的值必须是整数: %q", op, value)
+		}
+		req.Values = []string{value}
+	default:
+		value := l.readIdentifier()
+		if err := validateLabelValue(value); err != nil {
+			return LabelRequirement{}, err
+		}
+		req.Values = []string{value}
+	}
+	return req, nil
+}
+
+// 判断单个条件是否匹配，语义与 upstream labels.Requirement.Matches 一致
+func (r LabelRequirement) Matches(labels map[string]string) bool {
+	value, exists := labels[r.Key]
+	switch r.Operator {
+	case selectorIn, selectorEquals, selectorDoubleEquals:
+		return exists && containsString(r.Values, value)
+	case selectorNotIn, selectorNotEquals:
+		return !exists || !containsString(r.Values, value)
+	case selectorExists:
+		return exists
+	case selectorDoesNotExist:
+		return !exists
+	case selectorGreaterThan, selectorLessThan:
+		if !exists {
+			return false
+		}
+		have, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		want, err := strconv.ParseInt(r.value(), 10, 64)
+		if err != nil {
+			return false
+		}
+		if r.Operator == selectorGreaterThan {
+			return have > want
+		}
+		return have < want
+	}
+	return false
+}
+
+func (r LabelRequirement) String() string {
+	switch r.Operator {
+	case selectorExists:
+		return r.Key
+	case selectorDoesNotExist:
+		return "!" + r.Key
+	case selectorIn, selectorNotIn:
+		return r.Key + " " + r.Operator + " (" + strings.Join(r.Values, ",") + ")"
+	case selectorGreaterThan:
+		return r.Key + ">" + r.value()
+	case selectorLessThan:
+		return r.Key + "<" + r.value()
+	}
+	return r.Key + r.Operator + r.value()
+}
+
+// 单值运算符的取值；手工构造的条件可能没有值，按空串处理
+func (r LabelRequirement) value() string {
+	if len(r.Values) == 0 {
+		return ""
+	}
+	return r.Values[0]
+}
+
+// 所有条件均匹配时返回 true
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, req := range s {
+		if !req.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s LabelSelector) String() string {
+	parts := make([]string, len(s))
+	for i, req := range s {
+		parts[i] = req.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// 单个字段选择器条件
+type FieldRequirement struct {
+	Field    string
+	Operator string
+	Value    string
+}
+
+// 字段选择器，所有条件为与关系
+type FieldSelector []FieldRequirement
+
+// 解析字段选择器，语法与 kubectl --field-selector 一致，支持 \, \= \! \\ 转义:
+//
+//	metadata.namespace!=default,status.phase=Running,spec.nodeName=
+func ParseFieldSelector(input string) (FieldSelector, error) {
+	var selector FieldSelector
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return selector, nil
+	}
+
+	for _, term := range splitEscaped(input, ',') {
+		if strings.TrimSpace(term) == "" {
+			return nil, errorf("字段选择器包含空条件")
+		}
+		req, err := parseFieldRequirement(term)
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, req)
+	}
+	return selector, nil
+}
+
+func parseFieldRequirement(term string) (FieldRequirement, error) {
+	for i := 0; i < len(term); i++ {
+		if term[i] == '\\' {
+			i++
+			continue
+		}
+		for _, op := range []string{"!=", "==", "="} {
+			if strings.HasPrefix(term[i:], op) {
+				field := strings.TrimSpace(unescapeFieldSelector(term[:i]))
+				if field == "" {
+					return FieldRequirement{}, errorf("字段选择器 %q 缺少字段名", term)
+				}
+				return FieldRequirement{
+					Field:    field,
+					Operator: op,
+					Value:    unescapeFieldSelector(term[i+len(op):]),
+				}, nil
+			}
+		}
+	}
+	return FieldRequirement{}, errorf("无效的字段选择器 %q: 应为 field=value、field==value 或 field!=value", term)
+}
+
+// 按未转义的分隔符拆分
+func splitEscaped(s string, sep byte) []string {
+	var parts []string
+	last := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == sep {
+			parts = append(parts, s[last:i])
+			last = i + 1
+		}
+	}
+	return append(parts, s[last:])
+}
+
+func unescapeFieldSelector(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// 字段选择器读取的字段值：按点分路径读取，不存在时为空串（与 upstream 中未设置字段的行为一致）
+func fieldSelectorValue(info ResourceInfo, field string) string {
+	switch field {
+	case "metadata.name":
+		return info.Name
+	case "metadata.namespace":
+		return info.Namespace
+	}
+	value := nestedValue(info.Parsed, strings.Split(field, ".")...)
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case map[string]interface{}, []interface{}:
+		return ""
+	default:
+		return stringValue(v)
+	}
+}
+
+func (s FieldSelector) Matches(info ResourceInfo) bool {
+	for _, req := range s {
+		value := fieldSelectorValue(info, req.Field)
+		if req.Operator == selectorNotEquals {
+			if value == req.Value {
+				return false
+			}
+		} else if value != req.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// metav1.LabelSelector 的 matchExpressions 运算符
+var matchExpressionOperators = map[string]string{
+	"In":           selectorIn,
+	"NotIn":        selectorNotIn,
+	"Exists":       selectorExists,
+	"DoesNotExist": selectorDoesNotExist,
+}
+
+// 将资源中的 metav1.LabelSelector（matchLabels、matchExpressions）转换为选择器；
+// 与 upstream LabelSelectorAsSelector 一致，未知运算符和 In/NotIn 的空值集合返回错误
+func labelSelectorFromObject(obj map[string]interface{}) (LabelSelector, error) {
+	var selector LabelSelector
+	for key, value := range stringMap(nestedMap(obj, "matchLabels")) {
+		selector = append(selector, LabelRequirement{Key: key, Operator: selectorEquals, Values: []string{value}})
+	}
+	for _, item := range nestedSlice(obj, "matchExpressions") {
+		expr, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		operator := nestedString(expr, "operator")
+		req := LabelRequirement{Key: nestedString(expr, "key"), Operator: matchExpressionOperators[operator]}
+		if req.Operator == "" {
+			return nil, errorf("matchExpressions 中不支持的运算符: %q", operator)
+		}
+		for _, v := range nestedSlice(expr, "values") {
+			req.Values = append(req.Values, stringValue(v))
+		}
+		if len(req.Values) == 0 && (req.Operator == selectorIn || req.Operator == selectorNotIn) {
+			return nil, errorf("%s 运算符的值集合不能为空", operator)
+		}
+		selector = append(selector, req)
+	}
+	sort.Slice(selector, func(i, j int) bool { return selector[i].Key < selector[j].Key })
+	return selector, nil
+}
@@ -0,0 +1,305 @@
+package main
+
+import "testing"
+
+func TestParseLabelSelector(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // 解析后 String() 的结果
+	}{
+		{"", ""},
+		{"   ", ""},
+		{"app=web", "app=web"},
+		{"app==web", "app==web"},
+		{"app!=web", "app!=web"},
+		{"env in (prod,staging)", "env in (prod,staging)"},
+		{"env notin (staging,prod)", "env notin (prod,staging)"},
+		{"release", "release"},
+		{"!legacy", "!legacy"},
+		{"replicas>2", "replicas>2"},
+		{"replicas<10", "replicas<10"},
+		{"example.com/team=infra", "example.com/team=infra"},
+		{"app=", "app="},
+		{"  tier != db ,  app = web  ", "app=web,tier!=db"},
+		{"env in ( prod , staging ),release", "env in (prod,staging),release"},
+		{"env in(prod)", "env in (prod)"},
+		{"tier=db,app=web,!legacy", "app=web,!legacy,tier=db"},
+	}
+	for _, tt := range tests {
+		selector, err := ParseLabelSelector(tt.input)
+		if err != nil {
+			t.Errorf("ParseLabelSelector(%q) 返回错误: %v", tt.input, err)
+			continue
+		}
+		if got := selector.String(); got != tt.want {
+			t.Errorf("ParseLabelSelector(%q).String() = %q, 期望 %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseLabelSelectorErrors(t *testing.T) {
+	tests := []string{
+		"app=web,",
+		"app=web , ",
+		",app=web",
+		"app=web,,tier=db",
+		"app web",
+		"env in prod",
+		"env in ()",
+		"env in (prod",
+		"env in (prod staging)",
+		"replicas>two",
+		"replicas<",
+		"-app=web",
+		"app-=web",
+		"bad_prefix/app=web",
+		"/app=web",
+		"a/b/c=web",
+		"!-legacy",
+		"app=-web",
+		"app=web-",
+		"env in (prod,-staging)",
+		"app=aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+	}
+	for _, input := range tests {
+		if selector, err := ParseLabelSelector(input); err == nil {
+			t.Errorf("ParseLabelSelector(%q) = %q, 期望返回错误", input, selector.String())
+		}
+	}
+}
+
+func TestLabelSelectorMatches(t *testing.T) {
+	labels := map[string]string{
+		"app":      "web",
+		"tier":     "frontend",
+		"replicas": "3",
+		"version":  "v2",
+	}
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"app=web", true},
+		{"app==web", true},
+		{"app=db", false},
+		{"app!=db", true},
+		{"app!=web", false},
+		{"missing!=web", true},
+		{"missing=web", false},
+		{"tier in (frontend,backend)", true},
+		{"tier in (backend)", false},
+		{"missing in (frontend)", false},
+		{"tier notin (backend)", true},
+		{"tier notin (frontend,backend)", false},
+		{"missing notin (frontend)", true},
+		{"app", true},
+		{"missing", false},
+		{"!missing", true},
+		{"!app", false},
+		{"replicas>2", true},
+		{"replicas>3", false},
+		{"replicas<4", true},
+		{"replicas<3", false},
+		{"missing>0", false},
+		{"missing<100", false},
+		{"version>1", false},
+		{"version<100", false},
+		{"app=web,tier in (frontend),!legacy,replicas>1", true},
+		{"app=web,tier=backend", false},
+	}
+	for _, tt := range tests {
+		selector, err := ParseLabelSelector(tt.selector)
+		if err != nil {
+			t.Fatalf("ParseLabelSelector(%q) 返回错误: %v", tt.selector, err)
+		}
+		if got := selector.Matches(labels); got != tt.want {
+			t.Errorf("%q 匹配 %v = %v, 期望 %v", tt.selector, labels, got, tt.want)
+		}
+	}
+}
+
+func TestLabelRequirementMatchesNilLabels(t *testing.T) {
+	tests := []struct {
+		req  LabelRequirement
+		want bool
+	}{
+		{LabelRequirement{Key: "app", Operator: selectorEquals, Values: []string{"web"}}, false},
+		{LabelRequirement{Key: "app", Operator: selectorNotEquals, Values: []string{"web"}}, true},
+		{LabelRequirement{Key: "app", Operator: selectorIn, Values: []string{"web"}}, false},
+		{LabelRequirement{Key: "app", Operator: selectorNotIn, Values: []string{"web"}}, true},
+		{LabelRequirement{Key: "app", Operator: selectorExists}, false},
+		{LabelRequirement{Key: "app", Operator: selectorDoesNotExist}, true},
+		{LabelRequirement{Key: "app", Operator: selectorGreaterThan, Values: []string{"1"}}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.req.Matches(nil); got != tt.want {
+			t.Errorf("%s 匹配空标签 = %v, 期望 %v", tt.req.String(), got, tt.want)
+		}
+	}
+}
+
+func TestParseFieldSelector(t *testing.T) {
+	tests := []struct {
+		input string
+		want  FieldSelector
+	}{
+		{"", nil},
+		{"metadata.name=web", FieldSelector{{"metadata.name", "=", "web"}}},
+		{"metadata.name==web", FieldSelector{{"metadata.name", "==", "web"}}},
+		{"status.phase!=Running", FieldSelector{{"status.phase", "!=", "Running"}}},
+		{"spec.nodeName=", FieldSelector{{"spec.nodeName", "=", ""}}},
+		{" metadata.namespace = default", FieldSelector{{"metadata.namespace", "=", " default"}}},
+		{
+			"metadata.namespace!=kube-system,status.phase=Running",
+			FieldSelector{{"metadata.namespace", "!=", "kube-system"}, {"status.phase", "=", "Running"}},
+		},
+		{`metadata.name=a\,b`, FieldSelector{{"metadata.name", "=", "a,b"}}},
+		{`metadata.name=a\=b`, FieldSelector{{"metadata.name", "=", "a=b"}}},
+		{`metadata.name=a\\`, FieldSelector{{"metadata.name", "=", `a\`}}},
+	}
+	for _, tt := range tests {
+		got, err := ParseFieldSelector(tt.input)
+		if err != nil {
+			t.Errorf("ParseFieldSelector(%q) 返回错误: %v", tt.input, err)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseFieldSelector(%q) = %v, 期望 %v", tt.input, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("ParseFieldSelector(%q)[%d] = %+v, 期望 %+v", tt.input, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestParseFieldSelectorErrors(t *testing.T) {
+	tests := []string{
+		"metadata.name",
+		"=web",
+		" =web",
+		"metadata.name=web,",
+		"metadata.name=web,,status.phase=Running",
+		`metadata.name\=web`,
+	}
+	for _, input := range tests {
+		if selector, err := ParseFieldSelector(input); err == nil {
+			t.Errorf("ParseFieldSelector(%q) = %v, 期望返回错误", input, selector)
+		}
+	}
+}
+
+func TestFieldSelectorMatches(t *testing.T) {
+	pod := ResourceInfo{
+		Name:      "web-0",
+		Namespace: "default",
+		Kind:      "Pod",
+		Parsed: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "web-0", "namespace": "default"},
+			"spec":     map[string]interface{}{"nodeName": "node-1", "containers": []interface{}{}},
+			"status":   map[string]interface{}{"phase": "Running"},
+		},
+	}
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"metadata.name=web-0", true},
+		{"metadata.name==web-0", true},
+		{"metadata.name=web-1", false},
+		{"metadata.name!=web-1", true},
+		{"metadata.namespace=default", true},
+		{"metadata.namespace!=default", false},
+		{"metadata.namespace=kube-system", false},
+		{"status.phase=Running", true},
+		{"status.phase=Pending", false},
+		{"status.phase!=Succeeded", true},
+		{"spec.nodeName=node-1", true},
+		{"spec.nodeName=", false},
+		{"spec.schedulerName=", true},
+		{"spec.schedulerName!=", false},
+		{"spec.containers=", true},
+		{"metadata.namespace=default,status.phase=Running", true},
+		{"metadata.namespace=default,status.phase=Failed", false},
+	}
+	for _, tt := range tests {
+		selector, err := ParseFieldSelector(tt.selector)
+		if err != nil {
+			t.Fatalf("ParseFieldSelector(%q) 返回错误: %v", tt.selector, err)
+		}
+		if got := selector.Matches(pod); got != tt.want {
+			t.Errorf("%q 匹配 %s = %v, 期望 %v", tt.selector, pod.Name, got, tt.want)
+		}
+	}
+}
+
+func TestLabelRequirementStringWithoutValues(t *testing.T) {
+	tests := []struct {
+		req  LabelRequirement
+		want string
+	}{
+		{LabelRequirement{Key: "app", Operator: selectorEquals}, "app="},
+		{LabelRequirement{Key: "app", Operator: selectorGreaterThan}, "app>"},
+		{LabelRequirement{Key: "app", Operator: selectorLessThan}, "app<"},
+		{LabelRequirement{Key: "app", Operator: selectorIn}, "app in ()"},
+		{LabelRequirement{Key: "app"}, "app"},
+	}
+	for _, tt := range tests {
+		if got := tt.req.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, 期望 %q", tt.req, got, tt.want)
+		}
+	}
+	if (LabelRequirement{Key: "app", Operator: selectorGreaterThan}).Matches(map[string]string{"app": "1"}) {
+		t.Errorf("没有取值的 > 条件期望不匹配")
+	}
+}
+
+func TestLabelSelectorFromObject(t *testing.T) {
+	labels := map[string]string{"app": "web", "tier": "frontend"}
+	tests := []struct {
+		selector string
+		want     string // 转换后 String() 的结果
+		matches  bool
+	}{
+		{`{}`, "", true},
+		{`{"matchLabels": {"tier": "frontend", "app": "web"}}`, "app=web,tier=frontend", true},
+		{`{"matchLabels": {"app": "db"}}`, "app=db", false},
+		{`{"matchExpressions": [{"key": "tier", "operator": "In", "values": ["frontend", "backend"]}]}`, "tier in (frontend,backend)", true},
+		{`{"matchExpressions": [{"key": "tier", "operator": "NotIn", "values": ["frontend"]}]}`, "tier notin (frontend)", false},
+		{`{"matchExpressions": [{"key": "app", "operator": "Exists"}]}`, "app", true},
+		{`{"matchExpressions": [{"key": "canary", "operator": "DoesNotExist"}]}`, "!canary", true},
+		{`{"matchLabels": {"app": "web"}, "matchExpressions": [{"key": "canary", "operator": "Exists"}]}`, "app=web,canary", false},
+	}
+	for _, tt := range tests {
+		selector, err := labelSelectorFromObject(testObject(t, tt.selector).(map[string]interface{}))
+		if err != nil {
+			t.Errorf("labelSelectorFromObject(%s) 返回错误: %v", tt.selector, err)
+			continue
+		}
+		if got := selector.String(); got != tt.want {
+			t.Errorf("labelSelectorFromObject(%s).String() = %q, 期望 %q", tt.selector, got, tt.want)
+		}
+		if got := selector.Matches(labels); got != tt.matches {
+			t.Errorf("%s 匹配 %v = %v, 期望 %v", tt.selector, labels, got, tt.matches)
+		}
+	}
+}
+
+func TestLabelSelectorFromObjectErrors(t *testing.T) {
+	tests := []string{
+		`{"matchExpressions": [{"key": "tier", "operator": "Gt", "values": ["1"]}]}`,
+		`{"matchExpressions": [{"key": "tier", "operator": "in", "values": ["frontend"]}]}`,
+		`{"matchExpressions": [{"key": "tier", "values": ["frontend"]}]}`,
+		`{"matchExpressions": [{"key": "tier", "operator": "In"}]}`,
+		`{"matchExpressions": [{"key": "tier", "operator": "NotIn", "values": []}]}`,
+	}
+	for _, input := range tests {
+		if selector, err := labelSelectorFromObject(testObject(t, input).(map[string]interface{})); err == nil {
+			t.Errorf("labelSelectorFromObject(%s) = %q, 期望返回错误", input, selector.String())
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// 访问模式缩写，与 kubectl get pv/pvc 的输出一致
+var accessModeShort = map[string]string{
+	"ReadWriteOnce":    "RWO",
+	"ReadOnlyMany":     "ROX",
+	"ReadWriteMany":    "RWX",
+	"ReadWriteOncePod": "RWOP",
+}
+
+func accessModes(obj map[string]interface{}) string {
+	var modes []string
+	for _, item := range nestedSlice(obj, "spec", "accessModes") {
+		mode := stringValue(item)
+		if short, ok := accessModeShort[mode]; ok {
+			mode = short
+		}
+		modes = append(modes, mode)
+	}
+	return strings.Join(modes, ",")
+}
+
+type StorageClassSummary struct {
+	Index                int
+	Name                 string
+	Provisioner          string
+	ReclaimPolicy        string
+	VolumeBindingMode    string
+	AllowVolumeExpansion bool
+	Default              bool
+	Claims               int
+	Volumes              int
+}
+
+// PVC 与其绑定的 PV；Index 或 VolumeIndex 为 -1 表示对应资源未加载
+type StorageBinding struct {
+	Index        int
+	Namespace    string
+	Name         string
+	Phase        string
+	StorageClass string
+	Requested    string
+	AccessModes  string
+
+	VolumeIndex   int
+	VolumeName    string
+	VolumePhase   string
+	Capacity      string
+	ReclaimPolicy string
+	Provisioner   string
+
+	UsedBy  []ResourceRef // 挂载该 PVC 的 Pod 和工作负载
+	Problem string
+}
+
+type StorageReport struct {
+	Classes  []StorageClassSummary
+	Bindings []StorageBinding
+	Problems int
+}
+
+// 是否加载了存储相关资源
+func (r StorageReport) Present() bool {
+	return len(r.Classes) > 0 || len(r.Bindings) > 0
+}
+
+// 建立 PVC ↔ PV ↔ StorageClass 的对应关系。依赖 buildReferenceReport 填充的 UsedBy
+func buildStorageReport(infos []ResourceInfo, lang string) StorageReport {
+	var report StorageReport
+	classIndex := make(map[string]int)
+	volumeIndex := make(map[string]int)
+	defaultClass := ""
+	for i, info := range infos {
+		switch info.Kind {
+		case "StorageClass":
+			class := StorageClassSummary{
+				Index:                i,
+				Name:                 info.Name,
+				Provisioner:          nestedString(info.Parsed, "provisioner"),
+				ReclaimPolicy:        nestedString(info.Parsed, "reclaimPolicy"),
+				VolumeBindingMode:    nestedString(info.Parsed, "volumeBindingMode"),
+				AllowVolumeExpansion: info.Parsed["allowVolumeExpansion"] == true,
+				Default:              resourceAnnotations(info)["storageclass.kubernetes.io/is-default-class"] == "true",
+			}
+			if class.ReclaimPolicy == "" {
+				class.ReclaimPolicy = "Delete"
+			}
+			if class.VolumeBindingMode == "" {
+				class.VolumeBindingMode = "Immediate"
+			}
+			if class.Default {
+				defaultClass = info.Name
+			}
+			classIndex[info.Name] = len(report.Classes)
+			report.Classes = append(report.Classes, class)
+		case "PersistentVolume":
+			volumeIndex[info.Name] = i
+		}
+	}
+
+	// 先处理 PVC，再补充没有对应已加载 PVC 的 PV
+	claimed := make(map[int]bool)
+	for i, info := range infos {
+		if info.Kind != "PersistentVolumeClaim" {
+			continue
+		}
+		b := StorageBinding{
+			Index:       i,
+			Namespace:   info.Namespace,
+			Name:        info.Name,
+			Phase:       nestedString(info.Parsed, "status", "phase"),
+			Requested:   nestedString(info.Parsed, "spec", "resources", "requests", "storage"),
+			AccessModes: accessModes(info.Parsed),
+			VolumeName:  nestedString(info.Parsed, "spec", "volumeName"),
+			VolumeIndex: -1,
+			Capacity:    nestedString(info.Parsed, "status", "capacity", "storage"),
+			UsedBy:      info.UsedBy,
+		}
+		// 未设置 storageClassName 时使用默认 StorageClass，设置为空字符串表示不使用
+		if class, ok := nestedValue(info.Parsed, "spec", "storageClassName").(string); ok {
+			b.StorageClass = class
+		} else {
+			b.StorageClass = defaultClass
+		}
+		if b.VolumeName == "" {
+			for _, pv := range infos {
+				if pv.Kind == "PersistentVolume" &&
+					nestedString(pv.Parsed, "spec", "claimRef", "namespace") == info.Namespace &&
+					nestedString(pv.Parsed, "spec", "claimRef", "name") == info.Name {
+					b.VolumeName = pv.Name
+				}
+			}
+		}
+		if v, ok := volumeIndex[b.VolumeName]; ok {
+			b.VolumeIndex = v
+			claimed[v] = true
+			fillVolume(&b, infos[v])
+		}
+
+		switch {
+		case b.Phase == "Pending":
+			b.Problem = tr(lang, "PVC 未绑定")
+		case b.Phase == "Lost":
+			b.Problem = tr(lang, "绑定的 PV 已丢失")
+		case b.StorageClass != "" && len(classIndex) > 0 && !hasKey(classIndex, b.StorageClass):
+			b.Problem = tr(lang, "StorageClass %s 不存在", b.StorageClass)
+		}
+		if c, ok := classIndex[b.StorageClass]; ok {
+			report.Classes[c].Claims++
+			if b.Provisioner == "" {
+				b.Provisioner = report.Classes[c].Provisioner
+			}
+		}
+		report.Bindings = append(report.Bindings, b)
+	}
+
+	for i, info := range infos {
+		if info.Kind != "PersistentVolume" {
+			continue
+		}
+		if c, ok := classIndex[nestedString(info.Parsed, "spec", "storageClassName")]; ok {
+			report.Classes[c].Volumes++
+		}
+		if claimed[i] {
+			continue
+		}
+		b := StorageBinding{
+			Index:        -1,
+			Namespace:    nestedString(info.Parsed, "spec", "claimRef", "namespace"),
+			Name:         nestedString(info.Parsed, "spec", "claimRef", "name"),
+			StorageClass: nestedString(info.Parsed, "spec", "storageClassName"),
+			VolumeIndex:  i,
+			VolumeName:   info.Name,
+		}
+		fillVolume(&b, info)
+		if c, ok := classIndex[b.StorageClass]; ok && b.Provisioner == "" {
+			b.Provisioner = report.Classes[c].Provisioner
+		}
+		switch b.VolumePhase {
+		case "Released":
+			b.Problem = tr(lang, "PVC 已删除，PV 未回收（回收策略 %s）", b.ReclaimPolicy)
+		case "Failed":
+			b.Problem = tr(lang, "PV 回收失败")
+		}
+		report.Bindings = append(report.Bindings, b)
+	}
+
+	for _, b := range report.Bindings {
+		if b.Problem != "" {
+			report.Problems++
+		}
+	}
+	// 有问题的排在前面
+	sort.SliceStable(report.Bindings, func(i, j int) bool {
+		return report.Bindings[i].Problem != "" && report.Bindings[j].Problem == ""
+	})
+	return report
+}
+
+func fillVolume(b *StorageBinding, pv ResourceInfo) {
+	b.VolumePhase = nestedString(pv.Parsed, "status", "phase")
+	b.Capacity = nestedString(pv.Parsed, "spec", "capacity", "storage")
+	b.ReclaimPolicy = nestedString(pv.Parsed, "spec", "persistentVolumeReclaimPolicy")
+	if b.AccessModes == "" {
+		b.AccessModes = accessModes(pv.Parsed)
+	}
+	if driver := nestedString(pv.Parsed, "spec", "csi", "driver"); driver != "" {
+		b.Provisioner = driver
+	} else if p := resourceAnnotations(pv)["pv.kubernetes.io/provisioned-by"]; p != "" {
+		b.Provisioner = p
+	}
+}
+
+func hasKey(m map[string]int, key string) bool {
+	_, ok := m[key]
+	return ok
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"embed"
+	"errors"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// 页面模板、样式和脚本以文件形式内嵌。index.html 是入口，通过 {{ template "style.css" . }}
+// 等引用其余文件，页面仍是单个自包含的 HTML，可直接另存后离线查看
+//
+//go:embed templates/*
+var templateFiles embed.FS
+
+// 组成页面的模板文件。custom.css 内置为空，供只想改配色或品牌的团队单独覆盖
+var templateNames = []string{"index.html", "style.css", "custom.css", "app.js"}
+
+// --template-dir 指定的覆盖目录，其中存在的同名文件优先于内置文件
+var templateDir string
+
+// 读取模板文件，覆盖目录中没有的文件回退到内置版本
+func readTemplateFile(name string) ([]byte, error) {
+	if templateDir != "" {
+		data, err := os.ReadFile(filepath.Join(templateDir, name))
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return templateFiles.ReadFile("templates/" + name)
+}
+
+// 解析页面模板。每次请求重新解析，修改覆盖目录中的文件后刷新页面即可看到效果
+func loadPageTemplate(lang string) (*template.Template, error) {
+	tmpl := template.New("index.html").Funcs(templateFuncs(lang))
+	for _, name := range templateNames {
+		data, err := readTemplateFile(name)
+		if err != nil {
+			return nil, err
+		}
+		t := tmpl
+		if name != tmpl.Name() {
+			t = tmpl.New(name)
+		}
+		if _, err := t.Parse(string(data)); err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
@@ -0,0 +1,1133 @@
+// 资源数据
+const resources = {{ .ResourcesJSON }};
+
+// 当前页面语言及其消息目录，消息以中文原文为键；请求 API 时带上 locale，错误信息与页面语言一致
+const pageLang = {{ .Lang }};
+const messages = {{ .Messages }};
+
+// 翻译界面文字，%s 依次替换为参数；目录中没有的消息原样显示中文
+function t(msg, ...args) {
+  let i = 0;
+  return (messages[msg] || msg).replace(/%[sd]/g, () => String(args[i++]));
+}
+
+// 当前结构化视图中按字段路径索引的 schema 校验问题
+let currentSchemaIssues = {};
+
+// 字段的 schema 说明：描述、类型、默认值和可选值，用作悬停提示
+function schemaTooltip(schema) {
+  const lines = [];
+  if (schema.description) lines.push(schema.description);
+  if (schema.type || schema.intOrString) lines.push(t('类型: %s', schemaTypeName(schema)));
+  // 内置 API 文档中字符串和结构体字段的默认值多为 "" 或 {}，不显示
+  const emptyDefault = schema.default === '' || (schema.default && typeof schema.default === 'object' && Object.keys(schema.default).length === 0);
+  if (schema.default !== undefined && !emptyDefault) lines.push(t('默认值: %s', JSON.stringify(schema.default)));
+  if (schema.enum) lines.push(t('可选值: %s', schema.enum.map(v => JSON.stringify(v)).join(', ')));
+  return lines.join('\n');
+}
+
+function schemaTypeName(schema) {
+  if (schema.intOrString) return 'int-or-string';
+  if (schema.type === 'array' && schema.items && schema.items.type) return schema.items.type + '[]';
+  return schema.type + (schema.format ? ' (' + schema.format + ')' : '');
+}
+
+// 字段的子 schema；未在 properties 中声明时使用 additionalProperties
+function childSchema(schema, key) {
+  if (!schema) return null;
+  if (schema.properties && schema.properties[key]) return schema.properties[key];
+  return schema.additionalProperties || null;
+}
+
+// 字段名标签，带 schema 提示和校验标记
+function renderKeyLabel(k, schema, path, icon = '🔑 ') {
+  let cls = 'key-label';
+  const tips = [];
+  if (schema) {
+    const tip = schemaTooltip(schema);
+    if (tip) tips.push(tip);
+  }
+  // 只有 CRD 校验会产生问题；没有对应 schema 的问题字段即未声明的字段
+  const issues = currentSchemaIssues[path];
+  if (issues) {
+    cls += schema ? ' schema-invalid' : ' schema-unknown';
+    tips.unshift(issues.map(m => '⚠️ ' + m).join('\n'));
+  }
+  let html = '<div class="' + cls + '"' + (tips.length ? ' title="' + escapeHtml(tips.join('\n\n')).replace(/"/g, '&quot;') + '"' : '') + '>' + icon + escapeHtml(k);
+  if (schema && (schema.type || schema.intOrString)) {
+    html += '<span class="schema-type">' + escapeHtml(schemaTypeName(schema)) + '</span>';
+  }
+  return html + '</div>';
+}
+
+function renderValue(value, key = '', schema = null, path = '') {
+  if (value === null || value === undefined) {
+    return '<span class="value-null">null</span>';
+  }
+  
+  if (typeof value === 'string') {
+    return '<span class="value-string">"' + escapeHtml(value) + '"</span>';
+  }
+  
+  if (typeof value === 'number') {
+    return '<span class="value-number">' + value + '</span>';
+  }
+  
+  if (typeof value === 'boolean') {
+    return '<span class="value-boolean">' + value + '</span>';
+  }
+  
+  if (Array.isArray(value)) {
+    if (value.length === 0) {
+      return '<span class="value-null">[]</span>';
+    }
+    
+    let html = '<div class="value-array">';
+    html += '<strong>' + t('📋 数组 (%s 项)', value.length) + '</strong>';
+    const itemSchema = schema ? schema.items || null : null;
+    value.forEach((item, index) => {
+      const itemPath = path + '[' + index + ']';
+      const issues = currentSchemaIssues[itemPath];
+      const label = issues
+        ? '<strong class="array-index schema-invalid" title="' + escapeHtml(issues.join('\n')).replace(/"/g, '&quot;') + '">🔸 [' + index + ']</strong>'
+        : '<strong>🔸 [' + index + ']</strong>';
+      html += '<div class="array-item">';
+      if (typeof item === 'object' && item !== null) {
+        html += label + '<br>';
+        html += renderValue(item, '', itemSchema, itemPath);
+      } else {
+        html += label + ' ' + renderValue(item, '', itemSchema, itemPath);
+      }
+      html += '</div>';
+    });
+    html += '</div>';
+    return html;
+  }
+  
+  if (typeof value === 'object') {
+    const keys = Object.keys(value);
+    if (keys.length === 0) {
+      return '<span class="value-null">{}</span>';
+    }
+    
+    let html = '<div class="value-object">';
+    html += '<strong>' + t('📦 对象 (%s 个字段)', keys.length) + '</strong>';
+    html += '<div class="key-value-grid" style="margin-top: 10px;">';
+    
+    keys.forEach(k => {
+      const fieldSchema = childSchema(schema, k);
+      const fieldPath = path ? path + '.' + k : k;
+      html += renderKeyLabel(k, fieldSchema, fieldPath);
+      html += '<div class="value-content">' + renderValue(value[k], k, fieldSchema, fieldPath) + '</div>';
+    });
+    
+    html += '</div></div>';
+    return html;
+  }
+  
+  return '<span class="value-string">' + escapeHtml(String(value)) + '</span>';
+}
+
+function escapeHtml(text) {
+  const div = document.createElement('div');
+  div.textContent = text;
+  return div.innerHTML;
+}
+
+// schemaInfo 为 /api/v1/schema 的响应（可选），issues 为服务端的 CRD 校验结果
+function renderStructuredResource(parsedResource, schemaInfo = null, issues = null) {
+  const schema = schemaInfo ? schemaInfo.schema : null;
+  try {
+    if (!parsedResource || typeof parsedResource !== 'object') {
+      return '<p>' + t('无法解析资源结构') + '</p>';
+    }
+    
+    // 生成结构化 HTML
+    let html = '';
+    currentSchemaIssues = {};
+    (issues || []).forEach(issue => {
+      (currentSchemaIssues[issue.path] = currentSchemaIssues[issue.path] || []).push(issue.message);
+    });
+    if (issues && issues.length > 0) {
+      html += '<div class="schema-issues"><strong>' + t('⚠️ 不符合 CRD schema (%s)', issues.length) + '</strong><ul>';
+      issues.forEach(issue => {
+        html += '<li><code>' + escapeHtml(issue.path) + '</code> ' + escapeHtml(issue.message) + '</li>';
+      });
+      html += '</ul></div>';
+    }
+    if (schemaInfo) {
+      const sources = {
+        crd: t('📐 已按 CRD schema 标注字段'),
+        cluster: t('📖 字段说明来自集群 OpenAPI'),
+        cache: t('📖 字段说明来自 OpenAPI 磁盘缓存（集群不可用）'),
+        bundled: t('📖 字段说明来自内置离线文档包（Kubernetes %s，仅包含常用字段）', escapeHtml(schemaInfo.version || ''))
+      };
+      html += '<div class="schema-note">' + (sources[schemaInfo.source] || '') + t('，悬停字段名查看说明、默认值和可选值') + '</div>';
+    }
+    
+    // 主要部分
+    const sections = [
+      { key: 'apiVersion', title: t('API 版本'), icon: '🔖' },
+      { key: 'kind', title: t('资源类型'), icon: '📦' },
+      { key: 'metadata', title: t('元数据'), icon: '📋' },
+      { key: 'spec', title: t('规格配置'), icon: '⚙️' },
+      { key: 'status', title: t('状态信息'), icon: '📊' },
+      { key: 'data', title: t('数据'), icon: '💾' },
+      { key: 'stringData', title: t('字符串数据'), icon: '📝' },
+      { key: 'rules', title: t('规则'), icon: '📜' },
+      { key: 'subjects', title: t('主体'), icon: '👥' },
+      { key: 'roleRef', title: t('角色引用'), icon: '🔗' }
+    ];
+    
+    sections.forEach(section => {
+      if (parsedResource[section.key] !== undefined) {
+        html += '<div class="resource-section">';
+        html += '<div class="section-header" onclick="toggleSection(this)">';
+        html += '<span>' + section.icon + ' ' + section.title + '</span>';
+        html += '<span class="toggle-icon">▼</span>';
+        html += '</div>';
+        html += '<div class="section-content">';
+        if (currentSchemaIssues[section.key]) {
+          html += '<div class="schema-issues">' + currentSchemaIssues[section.key].map(m => '⚠️ ' + escapeHtml(m)).join('<br>') + '</div>';
+        }
+        html += renderValue(parsedResource[section.key], section.key, childSchema(schema, section.key), section.key);
+        html += '</div>';
+        html += '</div>';
+      }
+    });
+    
+    // 其他字段
+    const otherKeys = Object.keys(parsedResource).filter(key => 
+      !sections.some(section => section.key === key)
+    );
+    
+    if (otherKeys.length > 0) {
+      html += '<div class="resource-section">';
+      html += '<div class="section-header" onclick="toggleSection(this)">';
+      html += '<span>' + t('🔧 其他字段') + '</span>';
+      html += '<span class="toggle-icon">▼</span>';
+      html += '</div>';
+      html += '<div class="section-content">';
+      html += '<div class="key-value-grid">';
+      otherKeys.forEach(key => {
+        const fieldSchema = childSchema(schema, key);
+        html += renderKeyLabel(key, fieldSchema, key, '');
+        html += '<div class="value-content">' + renderValue(parsedResource[key], key, fieldSchema, key) + '</div>';
+      });
+      html += '</div>';
+      html += '</div>';
+      html += '</div>';
+    }
+    
+    return html || '<p>' + t('无法解析资源结构') + '</p>';
+    
+  } catch (error) {
+    return '<p>' + t('解析错误: %s', escapeHtml(error.message)) + '</p>';
+  }
+}
+
+function renderManagedFields(entries) {
+  if (!entries || entries.length === 0) {
+    return '<p>' + t('该资源没有 managedFields 信息') + '</p>';
+  }
+  
+  // 展开为 "字段 -> 管理者" 行，按字段路径排序
+  const rows = [];
+  entries.forEach(entry => {
+    (entry.fields || []).forEach(field => {
+      rows.push({ field: field, entry: entry });
+    });
+  });
+  rows.sort((a, b) => a.field.localeCompare(b.field));
+  
+  let html = '<table class="data-table">';
+  html += '<thead><tr><th>' + t('字段') + '</th><th>' + t('管理者') + '</th><th>' + t('操作') + '</th><th>' + t('子资源') + '</th><th>' + t('时间') + '</th></tr></thead><tbody>';
+  rows.forEach(row => {
+    html += '<tr>';
+    html += '<td class="field-path">' + escapeHtml(row.field) + '</td>';
+    html += '<td><strong>' + escapeHtml(row.entry.manager || '-') + '</strong></td>';
+    html += '<td>' + escapeHtml(row.entry.operation || '-') + '</td>';
+    html += '<td>' + escapeHtml(row.entry.subresource || '-') + '</td>';
+    html += '<td>' + escapeHtml(row.entry.time || '-') + '</td>';
+    html += '</tr>';
+  });
+  html += '</tbody></table>';
+  return html;
+}
+
+// ========== YAML 高亮、折叠与路径 ==========
+let currentYaml = '';
+let yamlLines = [];
+let selectedYamlPath = '';
+
+// 将 YAML 键转换为 kubectl JSONPath 片段
+function jsonPathSegment(key) {
+  return '.' + key.replace(/\./g, '\\.');
+}
+
+function unquoteYamlKey(key) {
+  if (key.length >= 2 && (key[0] === '"' || key[0] === "'") && key[key.length - 1] === key[0]) {
+    return key.slice(1, -1);
+  }
+  return key;
+}
+
+// 拆分 "key: value"，忽略引号内的冒号
+function splitYamlKey(text) {
+  let quote = '';
+  for (let i = 0; i < text.length; i++) {
+    const c = text[i];
+    if (quote) {
+      if (c === quote) quote = '';
+      continue;
+    }
+    if (c === '"' || c === "'") {
+      if (i === 0) quote = c;
+      continue;
+    }
+    if (c === '#' && (i === 0 || text[i - 1] === ' ')) {
+      return null;
+    }
+    if (c === ':' && (i === text.length - 1 || text[i + 1] === ' ')) {
+      return { key: text.slice(0, i), rest: text.slice(i + 1) };
+    }
+  }
+  return null;
+}
+
+function highlightYamlScalar(text) {
+  let value = text;
+  let comment = '';
+  const commentIndex = value.search(/\s#/);
+  if (commentIndex >= 0 && !/^["']/.test(value.trim())) {
+    comment = value.slice(commentIndex);
+    value = value.slice(0, commentIndex);
+  }
+  
+  const trimmed = value.trim();
+  const lead = value.slice(0, value.length - value.trimStart().length);
+  let cls = 'yaml-str';
+  if (trimmed === '') {
+    cls = '';
+  } else if (/^(true|false|True|False|TRUE|FALSE)$/.test(trimmed)) {
+    cls = 'yaml-bool';
+  } else if (/^(null|Null|NULL|~)$/.test(trimmed)) {
+    cls = 'yaml-null';
+  } else if (/^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$/.test(trimmed)) {
+    cls = 'yaml-num';
+  } else if (/^[|>][-+]?$/.test(trimmed) || trimmed === '{}' || trimmed === '[]') {
+    cls = 'yaml-punct';
+  }
+  
+  let html = escapeHtml(lead);
+  html += cls ? '<span class="' + cls + '">' + escapeHtml(trimmed) + '</span>' : escapeHtml(trimmed);
+  html += escapeHtml(value.slice(lead.length + trimmed.length));
+  if (comment) {
+    html += '<span class="yaml-comment">' + escapeHtml(comment) + '</span>';
+  }
+  return html;
+}
+
+// 逐行解析 YAML：计算缩进、JSONPath、折叠范围并生成高亮 HTML
+function renderYaml(text) {
+  const lines = (text || '').replace(/\n$/, '').split('\n');
+  const frames = [];
+  let blockScalarIndent = -1;
+  let blockScalarPath = '';
+  yamlLines = [];
+  
+  lines.forEach(raw => {
+    const indent = raw.length - raw.trimStart().length;
+    const content = raw.trim();
+    const info = { indent: indent, blank: content === '', path: '', html: '', isItem: false };
+    
+    if (blockScalarIndent >= 0 && (content === '' || indent > blockScalarIndent)) {
+      info.path = blockScalarPath;
+      info.html = '<span class="yaml-str">' + escapeHtml(raw) + '</span>';
+      info.blank = true;
+      yamlLines.push(info);
+      return;
+    }
+    blockScalarIndent = -1;
+    
+    if (content === '' || content.startsWith('#') || content === '---') {
+      info.html = content === '' ? '' : '<span class="yaml-comment">' + escapeHtml(raw) + '</span>';
+      info.blank = true;
+      yamlLines.push(info);
+      return;
+    }
+    
+    let column = indent;
+    let body = content;
+    let html = escapeHtml(raw.slice(0, indent));
+    
+    if (body === '-' || body.startsWith('- ')) {
+      info.isItem = true;
+      while (frames.length && frames[frames.length - 1].indent > column) frames.pop();
+      const top = frames[frames.length - 1];
+      if (top && top.list && top.indent === column) {
+        top.index++;
+      } else {
+        frames.push({ indent: column, list: true, index: 0 });
+      }
+      html += '<span class="yaml-punct">-</span>';
+      const after = body.slice(1);
+      const spaces = after.length - after.trimStart().length;
+      html += escapeHtml(after.slice(0, spaces));
+      body = after.trimStart();
+      column += 1 + spaces;
+    } else {
+      while (frames.length && frames[frames.length - 1].indent >= column) frames.pop();
+    }
+    
+    const kv = body ? splitYamlKey(body) : null;
+    if (kv) {
+      const key = unquoteYamlKey(kv.key.trim());
+      frames.push({ indent: column, list: false, key: key });
+      html += '<span class="yaml-key">' + escapeHtml(kv.key) + '</span><span class="yaml-punct">:</span>';
+      html += highlightYamlScalar(kv.rest);
+      if (/^\s*[|>][-+]?\s*$/.test(kv.rest)) {
+        blockScalarIndent = indent;
+      }
+    } else {
+      html += highlightYamlScalar(body);
+    }
+    
+    info.path = frames.map(f => f.list ? '[' + f.index + ']' : jsonPathSegment(f.key)).join('');
+    if (blockScalarIndent >= 0) {
+      blockScalarPath = info.path;
+    }
+    info.html = html;
+    yamlLines.push(info);
+  });
+  
+  // 计算折叠范围：后续缩进更深的行（或同缩进的列表项）属于该块
+  yamlLines.forEach((line, i) => {
+    line.end = i;
+    if (line.blank) return;
+    for (let j = i + 1; j < yamlLines.length; j++) {
+      const next = yamlLines[j];
+      if (next.blank && next.html === '') continue;
+      const inBlock = next.indent > line.indent || (next.blank && next.path === line.path && next.path !== '') ||
+        (next.indent === line.indent && next.isItem && !line.isItem);
+      if (!inBlock) break;
+      line.end = j;
+    }
+  });
+  
+  let html = '';
+  yamlLines.forEach((line, i) => {
+    const foldable = line.end > i;
+    html += '<div class="yaml-line" id="yaml-line-' + i + '" onclick="selectYamlLine(' + i + ')">';
+    html += '<span class="yaml-ln">' + (i + 1) + '</span>';
+    html += '<span class="yaml-fold' + (foldable ? ' foldable' : '') + '"' +
+      (foldable ? ' onclick="toggleYamlFold(event, ' + i + ')">▼' : '>') + '</span>';
+    html += '<span class="yaml-text">' + line.html + '</span>';
+    html += '</div>';
+  });
+  return html;
+}
+
+function setYamlFold(i, folded) {
+  const line = yamlLines[i];
+  const el = document.getElementById('yaml-line-' + i);
+  if (!line || !el || line.end <= i) return;
+  line.folded = folded;
+  el.classList.toggle('folded', folded);
+  el.querySelector('.yaml-fold').textContent = folded ? '▶' : '▼';
+  
+  for (let j = i + 1; j <= line.end; j++) {
+    document.getElementById('yaml-line-' + j).style.display = folded ? 'none' : '';
+    // 展开时保持内部已折叠的子块
+    if (!folded && yamlLines[j].folded) {
+      j = yamlLines[j].end;
+    }
+  }
+}
+
+function toggleYamlFold(event, i) {
+  event.stopPropagation();
+  setYamlFold(i, !yamlLines[i].folded);
+}
+
+function setAllYamlFolds(folded) {
+  if (folded) {
+    // 由内向外折叠，保证每个子块都记录折叠状态
+    for (let i = yamlLines.length - 1; i >= 0; i--) {
+      if (yamlLines[i].end > i && yamlLines[i].indent > 0) setYamlFold(i, true);
+    }
+  } else {
+    for (let i = 0; i < yamlLines.length; i++) {
+      if (yamlLines[i].folded) setYamlFold(i, false);
+    }
+  }
+}
+
+function selectYamlLine(i) {
+  document.querySelectorAll('.yaml-line.selected').forEach(el => el.classList.remove('selected'));
+  document.getElementById('yaml-line-' + i).classList.add('selected');
+  selectedYamlPath = yamlLines[i].path ? '{' + yamlLines[i].path + '}' : '';
+  const pathEl = document.getElementById('yamlPath');
+  pathEl.textContent = selectedYamlPath || t('(无路径)');
+  pathEl.title = selectedYamlPath;
+}
+
+function resetYamlPath() {
+  selectedYamlPath = '';
+  const pathEl = document.getElementById('yamlPath');
+  pathEl.textContent = t('点击任意行获取 JSONPath');
+  pathEl.title = t('点击 YAML 行以选择路径');
+}
+
+// 复制到剪贴板；非 HTTPS 环境下 navigator.clipboard 不可用，退回 execCommand
+function copyText(text, label) {
+  const done = () => showCopyToast(t('%s 已复制', label));
+  if (navigator.clipboard && window.isSecureContext) {
+    navigator.clipboard.writeText(text).then(done, () => fallbackCopy(text, done));
+  } else {
+    fallbackCopy(text, done);
+  }
+}
+
+function fallbackCopy(text, done) {
+  const textarea = document.createElement('textarea');
+  textarea.value = text;
+  textarea.style.position = 'fixed';
+  textarea.style.opacity = '0';
+  document.body.appendChild(textarea);
+  textarea.select();
+  try {
+    document.execCommand('copy');
+    done();
+  } catch (e) {
+    alert(t('复制失败: %s', e.message));
+  }
+  document.body.removeChild(textarea);
+}
+
+function showCopyToast(message) {
+  const pathEl = document.getElementById('yamlPath');
+  const previous = pathEl.textContent;
+  pathEl.textContent = '✅ ' + message;
+  setTimeout(() => { pathEl.textContent = previous; }, 1200);
+}
+
+function copyYaml() {
+  copyText(currentYaml, 'YAML');
+}
+
+function copyYamlPath() {
+  if (!selectedYamlPath) {
+    alert(t('请先点击 YAML 中的一行'));
+    return;
+  }
+  copyText(selectedYamlPath, t('路径'));
+}
+
+function toggleSection(header) {
+  const content = header.nextElementSibling;
+  const icon = header.querySelector('.toggle-icon');
+  
+  if (content.classList.contains('collapsed')) {
+    content.classList.remove('collapsed');
+    header.classList.remove('collapsed');
+    icon.textContent = '▼';
+  } else {
+    content.classList.add('collapsed');
+    header.classList.add('collapsed');
+    icon.textContent = '▶';
+  }
+}
+
+function switchTab(tabName) {
+  // 隐藏所有标签页
+  document.querySelectorAll('.tab-content').forEach(tab => {
+    tab.classList.remove('active');
+  });
+  
+  // 移除所有按钮的活动状态
+  document.querySelectorAll('.tab-button').forEach(btn => {
+    btn.classList.remove('active');
+  });
+  
+  // 显示选中的标签页
+  document.getElementById(tabName + 'Tab').classList.add('active');
+  event.target.classList.add('active');
+}
+
+// ========== 导出 ==========
+let currentResourceIndex = -1;
+
+function visibleResourceIndices() {
+  return Array.from(document.querySelectorAll('.resource-card'))
+    .filter(card => card.style.display !== 'none')
+    .map(card => card.dataset.index);
+}
+
+function exportUrl(format, indices) {
+  const params = new URLSearchParams({ format: format, locale: pageLang });
+  if (document.getElementById('exportClean').checked) {
+    params.set('clean', '1');
+  }
+  if (indices) {
+    params.set('index', indices.join(','));
+  }
+  return '/api/export?' + params.toString();
+}
+
+function exportResources(scope) {
+  const format = document.getElementById('exportFormat').value;
+  if (scope === 'all') {
+    window.location.href = exportUrl(format);
+    return;
+  }
+  const indices = visibleResourceIndices();
+  if (indices.length === 0) {
+    alert(t('当前列表中没有可导出的资源'));
+    return;
+  }
+  window.location.href = exportUrl(format, indices);
+}
+
+function downloadCurrentResource(format) {
+  if (currentResourceIndex < 0) return;
+  window.location.href = exportUrl(format, [currentResourceIndex]);
+}
+
+// ========== 页面切换与链接 ==========
+// 切换界面语言：写入 cookie 供刷新和 API 请求使用，并通过 ?locale= 重新加载页面，保留当前视图
+function switchLanguage(lang) {
+  document.cookie = 'kubectl-html-locale=' + encodeURIComponent(lang) + '; path=/; max-age=31536000; SameSite=Lax';
+  const params = new URLSearchParams(location.search);
+  params.set('locale', lang);
+  location.search = params.toString();
+}
+
+// 主题保存在 localStorage，页面 <head> 中的脚本在渲染前应用，避免闪烁
+const themeStorageKey = 'kubectl-html-theme';
+
+function switchTheme(theme) {
+  document.documentElement.dataset.theme = theme;
+  try {
+    localStorage.setItem(themeStorageKey, theme);
+  } catch (e) {
+    // 隐私模式等禁用本地存储时只对当前页面生效
+  }
+}
+
+(function () {
+  const select = document.querySelector('.theme-switch');
+  if (select) select.value = document.documentElement.dataset.theme || 'light';
+})();
+
+function switchView(name) {
+  const view = document.getElementById('view-' + name);
+  if (!view) return;
+  document.querySelectorAll('.view').forEach(v => v.classList.remove('active'));
+  document.querySelectorAll('.view-nav-btn').forEach(b => b.classList.toggle('active', b.dataset.view === name));
+  view.classList.add('active');
+  const hash = hashParams();
+  if (hash.get('view') !== name) {
+    hash.set('view', name);
+    setHashParams(hash);
+  }
+}
+
+function hashParams() {
+  return new URLSearchParams(location.hash.replace(/^#/, ''));
+}
+
+function setHashParams(params) {
+  history.replaceState(null, '', '#' + params.toString());
+}
+
+// 支持 #view=query&lang=jq&q=... 和 #resource=3 形式的链接
+function applyHash() {
+  const params = hashParams();
+  if (params.get('view')) {
+    switchView(params.get('view'));
+  }
+  if (params.get('view') === 'query' && params.get('q')) {
+    document.getElementById('queryLang').value = params.get('lang') || 'jsonpath';
+    document.getElementById('queryInput').value = params.get('q');
+    runQuery();
+  }
+  if (params.get('labelSelector') || params.get('fieldSelector')) {
+    document.getElementById('labelSelectorInput').value = params.get('labelSelector') || '';
+    document.getElementById('fieldSelectorInput').value = params.get('fieldSelector') || '';
+    applyFilters();
+  }
+  const index = parseInt(params.get('resource'), 10);
+  if (!isNaN(index) && resources[index]) {
+    showResourceModal(index);
+  }
+}
+
+// ========== 选择器筛选 ==========
+function applyFilters() {
+  const labelSelector = document.getElementById('labelSelectorInput').value.trim();
+  const fieldSelector = document.getElementById('fieldSelectorInput').value.trim();
+  const status = document.getElementById('filterStatus');
+  const cards = document.querySelectorAll('.resource-card');
+  
+  const hash = hashParams();
+  hash.delete('labelSelector');
+  hash.delete('fieldSelector');
+  if (labelSelector) hash.set('labelSelector', labelSelector);
+  if (fieldSelector) hash.set('fieldSelector', fieldSelector);
+  setHashParams(hash);
+  
+  if (!labelSelector && !fieldSelector) {
+    cards.forEach(card => { card.style.display = ''; });
+    status.className = 'query-status';
+    status.textContent = '';
+    return;
+  }
+  
+  const params = new URLSearchParams({ labelSelector: labelSelector, fieldSelector: fieldSelector, fields: 'index', locale: pageLang });
+  fetch('/api/v1/resources?' + params.toString())
+    .then(resp => resp.json())
+    .then(data => {
+      if (data.error) {
+        status.className = 'query-status error';
+        status.textContent = '❌ ' + data.error;
+        return;
+      }
+      const visible = {};
+      data.items.forEach(item => { visible[item.index] = true; });
+      cards.forEach(card => {
+        card.style.display = visible[card.dataset.index] ? '' : 'none';
+      });
+      status.className = 'query-status';
+      status.textContent = t('显示 %s / %s', data.total, cards.length);
+    })
+    .catch(err => {
+      status.className = 'query-status error';
+      status.textContent = t('❌ 请求失败: %s', err.message);
+    });
+}
+
+function clearFilters() {
+  document.getElementById('labelSelectorInput').value = '';
+  document.getElementById('fieldSelectorInput').value = '';
+  applyFilters();
+}
+
+// 按标签选择器筛选资源列表并切换到资源页面
+function applyLabelFilter(selector) {
+  document.getElementById('labelSelectorInput').value = selector;
+  document.getElementById('fieldSelectorInput').value = '';
+  switchView('resources');
+  applyFilters();
+}
+
+function filterFindings(severity) {
+  document.querySelectorAll('.lint-filter .yaml-tool-btn').forEach(b => b.classList.toggle('active', b.dataset.severity === severity));
+  document.querySelectorAll('#findingsTable tbody tr').forEach(row => {
+    row.style.display = !severity || row.dataset.severity === severity ? '' : 'none';
+  });
+}
+
+function applyFieldFilter(selector) {
+  document.getElementById('labelSelectorInput').value = '';
+  document.getElementById('fieldSelectorInput').value = selector;
+  switchView('resources');
+  applyFilters();
+}
+
+// ========== 查询控制台 ==========
+function useQueryExample(lang, query) {
+  document.getElementById('queryLang').value = lang;
+  document.getElementById('queryInput').value = query;
+  runQuery();
+}
+
+function formatQueryValue(value) {
+  if (value !== null && typeof value === 'object') {
+    return '<code>' + escapeHtml(JSON.stringify(value)) + '</code>';
+  }
+  return escapeHtml(String(value));
+}
+
+function runQuery() {
+  const lang = document.getElementById('queryLang').value;
+  const query = document.getElementById('queryInput').value.trim();
+  const status = document.getElementById('queryStatus');
+  const results = document.getElementById('queryResults');
+  if (!query) return;
+  
+  setHashParams(new URLSearchParams({ view: 'query', lang: lang, q: query }));
+  status.className = 'query-status';
+  status.textContent = t('查询中...');
+  
+  const params = new URLSearchParams({ lang: lang, q: query, locale: pageLang });
+  if (new URLSearchParams(location.search).get('clean')) {
+    params.set('clean', new URLSearchParams(location.search).get('clean'));
+  }
+  fetch('/api/v1/query?' + params.toString())
+    .then(resp => resp.json())
+    .then(data => {
+      if (data.error) {
+        status.className = 'query-status error';
+        status.textContent = '❌ ' + data.error;
+        results.innerHTML = '';
+        return;
+      }
+      let text = t('共 %s 条结果', data.count);
+      if (data.errors) {
+        text += t('，%s 个资源求值出错（如 %s）', data.errors, data.firstError);
+      }
+      status.textContent = text;
+      results.innerHTML = renderQueryResults(data.rows);
+    })
+    .catch(err => {
+      status.className = 'query-status error';
+      status.textContent = t('❌ 请求失败: %s', err.message);
+    });
+}
+
+// 结果均为扁平对象时按键展开为列，否则单列显示值
+function renderQueryResults(rows) {
+  if (rows.length === 0) {
+    return '<p>' + t('没有匹配的结果') + '</p>';
+  }
+  const allObjects = rows.every(r => r.value !== null && typeof r.value === 'object' && !Array.isArray(r.value));
+  let columns = [];
+  if (allObjects) {
+    const seen = {};
+    rows.forEach(r => Object.keys(r.value).forEach(k => {
+      if (!seen[k]) { seen[k] = true; columns.push(k); }
+    }));
+  }
+  
+  let html = '<table class="data-table"><thead><tr><th>' + t('类型') + '</th><th>' + t('命名空间') + '</th><th>' + t('名称') + '</th>';
+  if (allObjects) {
+    columns.forEach(c => { html += '<th>' + escapeHtml(c) + '</th>'; });
+  } else {
+    html += '<th>' + t('值') + '</th>';
+  }
+  html += '</tr></thead><tbody>';
+  rows.forEach(r => {
+    html += '<tr class="clickable" onclick="showResourceModal(' + r.index + ')" title="' + t('查看资源详情') + '">';
+    html += '<td>' + escapeHtml(r.kind) + '</td>';
+    html += '<td>' + escapeHtml(r.namespace || '-') + '</td>';
+    html += '<td><strong>' + escapeHtml(r.name) + '</strong></td>';
+    if (allObjects) {
+      columns.forEach(c => {
+        html += '<td>' + (r.value[c] === undefined ? '' : formatQueryValue(r.value[c])) + '</td>';
+      });
+    } else {
+      html += '<td class="field-path">' + formatQueryValue(r.value) + '</td>';
+    }
+    html += '</tr>';
+  });
+  html += '</tbody></table>';
+  return html;
+}
+
+// 按 apiVersion + kind 缓存的 schema 响应，没有 schema 的类型缓存为 null
+const schemaCache = {};
+
+function loadResourceSchema(resource) {
+  const key = resource.apiVersion + ' ' + resource.kind;
+  if (!(key in schemaCache)) {
+    schemaCache[key] = fetch('/api/v1/schema?apiVersion=' + encodeURIComponent(resource.apiVersion) + '&kind=' + encodeURIComponent(resource.kind))
+      .then(response => response.ok ? response.json() : null)
+      .catch(() => null);
+  }
+  return schemaCache[key];
+}
+
+function showResourceModal(index) {
+  const resource = resources[index];
+  currentResourceIndex = index;
+  const modal = document.getElementById('resourceModal');
+  const title = document.getElementById('modalTitle');
+  const subtitle = document.getElementById('modalSubtitle');
+  const yaml = document.getElementById('modalYaml');
+  const structured = document.getElementById('structuredContent');
+  
+  title.textContent = resource.name || 'Unknown Resource';
+  subtitle.textContent = resource.kind + (resource.namespace ? ' (' + resource.namespace + ')' : '') + ' - ' + resource.apiVersion;
+  currentYaml = resource.yaml;
+  yaml.innerHTML = renderYaml(resource.yaml);
+  resetYamlPath();
+  
+  // 生成结构化视图，自定义资源的 CRD schema 加载后重新渲染
+  structured.innerHTML = renderStructuredResource(resource.parsed, null, resource.schemaIssues);
+  loadResourceSchema(resource).then(schemaInfo => {
+    if (schemaInfo && currentResourceIndex === index) {
+      structured.innerHTML = renderStructuredResource(resource.parsed, schemaInfo, resource.schemaIssues);
+    }
+  });
+  document.getElementById('managedContent').innerHTML = renderManagedFields(resource.managedFields);
+  document.getElementById('certsTabButton').style.display = resource.certificates ? '' : 'none';
+  document.getElementById('certsContent').innerHTML = resource.certificates ? renderCertificates(resource.certificates) : '';
+  
+  const hasRefs = resource.uses || resource.usedBy;
+  document.getElementById('refsTabButton').style.display = hasRefs ? '' : 'none';
+  document.getElementById('refsContent').innerHTML = hasRefs ? renderReferences(resource) : '';
+  const hasContainers = resource.containers && resource.containers.length > 0;
+  document.getElementById('containersTabButton').style.display = hasContainers ? '' : 'none';
+  document.getElementById('containersContent').innerHTML = hasContainers ? renderContainers(resource.containers, resource.namespace) : '';
+  
+  document.getElementById('gitopsTabButton').style.display = resource.gitops ? '' : 'none';
+  document.getElementById('gitopsContent').innerHTML = resource.gitops ? renderGitOps(resource.gitops) : '';
+  
+  // Pod 默认显示容器面板，GitOps 对象显示同步详情，其他资源重置到结构化视图
+  const defaultTab = hasContainers ? 'containers' : (resource.gitops ? 'gitops' : 'structured');
+  document.querySelectorAll('.tab-content').forEach(tab => tab.classList.remove('active'));
+  document.querySelectorAll('.tab-button').forEach(btn => btn.classList.remove('active'));
+  document.getElementById(defaultTab + 'Tab').classList.add('active');
+  document.getElementById(defaultTab + 'TabButton').classList.add('active');
+  
+  // 阻止背景滚动
+  document.body.classList.add('modal-open');
+  modal.style.display = 'block';
+}
+
+// ========== 引用 ==========
+// 在已加载资源中查找，返回索引，未找到时返回 -1
+function findResourceIndex(kind, namespace, name) {
+  return resources.findIndex(r => r.kind === kind && r.name === name && (kind === 'PriorityClass' || r.namespace === namespace));
+}
+
+// 可跳转到目标资源模态框的链接，未加载时只显示文本
+function resourceLink(index, text) {
+  if (index < 0) return '<span class="ref-missing" title="' + t('未在已加载的资源中找到') + '">' + escapeHtml(text) + '</span>';
+  return '<span class="ref-link" onclick="showResourceModal(' + index + ')">' + escapeHtml(text) + '</span>';
+}
+
+function renderReferenceTable(refs, title) {
+  let html = '<h4>' + title + ' (' + refs.length + ')</h4>';
+  html += '<table class="data-table"><thead><tr><th>' + t('类型') + '</th><th>' + t('名称') + '</th><th>' + t('引用位置') + '</th></tr></thead><tbody>';
+  refs.forEach(ref => {
+    let name = resourceLink(ref.index, (ref.namespace ? ref.namespace + '/' : '') + ref.name);
+    if (ref.optional) name += ' <span class="label-chip static">optional</span>';
+    html += '<tr><td>' + escapeHtml(ref.kind) + '</td><td>' + name + '</td><td>' + escapeHtml(ref.via) + '</td></tr>';
+  });
+  html += '</tbody></table>';
+  return html;
+}
+
+function renderReferences(resource) {
+  let html = '';
+  if (resource.uses) html += renderReferenceTable(resource.uses, t('➡️ 使用'));
+  if (resource.usedBy) html += renderReferenceTable(resource.usedBy, t('⬅️ 被使用'));
+  return html;
+}
+
+// ========== GitOps ==========
+function renderGitOps(app) {
+  const rows = [
+    [t('工具'), app.tool + ' ' + app.kind],
+    [t('同步状态'), app.sync],
+    [t('健康状态'), app.health],
+    [t('信息'), app.message],
+    [t('来源'), app.source],
+    [t('路径 / Chart'), app.path],
+    [t('目标版本'), app.targetRevision],
+    [t('当前版本'), app.revision],
+    [t('部署目标'), app.destination],
+    [t('最近同步'), app.lastSync]
+  ];
+  let html = '<table class="data-table">';
+  rows.forEach(([label, value]) => {
+    if (value) html += '<tr><th>' + label + '</th><td class="field-path">' + escapeHtml(value) + '</td></tr>';
+  });
+  if (app.suspended) html += '<tr><th>' + t('已暂停') + '</th><td>' + t('spec.suspend 为 true，不会自动同步') + '</td></tr>';
+  html += '</table>';
+  
+  const managed = app.managed || [];
+  html += '<h4>' + t('📦 管理的资源 (%s)', managed.length) + '</h4>';
+  if (managed.length === 0) {
+    return html + '<p>' + t('没有找到管理的资源') + '</p>';
+  }
+  html += '<table class="data-table"><thead><tr><th>' + t('类型') + '</th><th>' + t('名称') + '</th><th>' + t('同步') + '</th><th>' + t('健康') + '</th><th>' + t('来源') + '</th></tr></thead><tbody>';
+  managed.forEach(m => {
+    const name = resourceLink(m.index, (m.namespace ? m.namespace + '/' : '') + m.name);
+    const sync = m.sync === 'OutOfSync' ? '<span class="lint-badge lint-error">OutOfSync</span>' : escapeHtml(m.sync || '-');
+    html += '<tr><td>' + escapeHtml(m.kind) + '</td><td>' + name + '</td><td>' + sync + '</td><td>' + escapeHtml(m.health || '-') + '</td><td>' + escapeHtml(m.tracked) + '</td></tr>';
+  });
+  html += '</tbody></table>';
+  return html;
+}
+
+// ========== 容器 ==========
+function renderTermination(term) {
+  let text = escapeHtml(term.reason || 'Terminated') + ' (' + t('退出码 %s', term.exitCode);
+  if (term.signal) text += ', ' + t('信号 %s', term.signal);
+  text += ')';
+  if (term.finishedAt) text += ' · ' + escapeHtml(term.finishedAt);
+  if (term.message) text += '<div class="container-message">' + escapeHtml(term.message) + '</div>';
+  return text;
+}
+
+// 环境变量或卷的来源，引用的 ConfigMap / Secret / PVC 可跳转
+function containerRefSource(item, namespace) {
+  if (!item.refKind) return escapeHtml(item.source || '-');
+  return resourceLink(findResourceIndex(item.refKind, namespace, item.refName), item.source);
+}
+
+function renderContainers(containers, namespace) {
+  const stateClass = { running: 'status-running', waiting: 'status-pending', terminated: 'status-failed' };
+  let html = '';
+  containers.forEach(c => {
+    // 正常退出的 init 容器不算失败
+    let cls = stateClass[c.state] || 'status-unknown';
+    if (c.state === 'terminated' && c.terminated && c.terminated.exitCode === 0) cls = 'status-running';
+    html += '<div class="container-card">';
+    html += '<div class="container-header">';
+    html += '<b>' + escapeHtml(c.name) + '</b>';
+    if (c.init) html += '<span class="label-kind">init</span>';
+    html += '<span class="status-badge ' + cls + '">' + escapeHtml(c.state || 'unknown') + (c.reason ? ': ' + escapeHtml(c.reason) : '') + '</span>';
+    if (c.state) {
+      html += '<span>' + (c.ready ? '✅ Ready' : t('⏳ 未就绪')) + '</span>';
+      html += '<span class="' + (c.restartCount > 0 ? 'container-restarts' : '') + '">' + t('🔁 重启 %s 次', c.restartCount) + '</span>';
+    }
+    html += '</div>';
+    
+    html += '<table class="data-table">';
+    html += '<tr><th>' + t('镜像') + '</th><td class="field-path">' + escapeHtml(c.image) + (c.imageID ? '<div class="container-message">' + escapeHtml(c.imageID) + '</div>' : '') + '</td></tr>';
+    if (c.startedAt) html += '<tr><th>' + t('启动时间') + '</th><td>' + escapeHtml(c.startedAt) + '</td></tr>';
+    if (c.message) html += '<tr><th>' + t('状态信息') + '</th><td>' + escapeHtml(c.message) + '</td></tr>';
+    if (c.terminated) html += '<tr><th>' + t('终止') + '</th><td>' + renderTermination(c.terminated) + '</td></tr>';
+    if (c.lastTermination) html += '<tr><th>' + t('上次终止') + '</th><td>' + renderTermination(c.lastTermination) + '</td></tr>';
+    if (c.ports) {
+      html += '<tr><th>' + t('端口') + '</th><td>' + c.ports.map(p => '<span class="label-chip static">' + escapeHtml(p) + '</span>').join('') + '</td></tr>';
+    }
+    html += '</table>';
+    
+    if (c.env || c.envFrom) {
+      html += '<h4>' + t('🌱 环境变量') + '</h4><table class="data-table"><thead><tr><th>' + t('名称') + '</th><th>' + t('值 / 来源') + '</th></tr></thead><tbody>';
+      (c.envFrom || []).forEach(e => {
+        html += '<tr><td>' + (e.name ? escapeHtml(e.name) + '*' : '<i>' + t('全部键') + '</i>') + '</td><td>📥 ' + containerRefSource(e, namespace) + '</td></tr>';
+      });
+      (c.env || []).forEach(e => {
+        const value = e.source ? '🔗 ' + containerRefSource(e, namespace) : '<span class="field-path">' + escapeHtml(e.value || '') + '</span>';
+        html += '<tr><td>' + escapeHtml(e.name) + '</td><td>' + value + '</td></tr>';
+      });
+      html += '</tbody></table>';
+    }
+    
+    if (c.mounts) {
+      html += '<h4>' + t('💾 卷挂载') + '</h4><table class="data-table"><thead><tr><th>' + t('挂载路径') + '</th><th>' + t('卷') + '</th><th>' + t('来源') + '</th></tr></thead><tbody>';
+      c.mounts.forEach(m => {
+        let path = escapeHtml(m.mountPath);
+        if (m.subPath) path += ' <small>(subPath: ' + escapeHtml(m.subPath) + ')</small>';
+        if (m.readOnly) path += ' <span class="label-chip static">ro</span>';
+        html += '<tr><td class="field-path">' + path + '</td><td>' + escapeHtml(m.volume) + '</td><td>' + containerRefSource(m, namespace) + '</td></tr>';
+      });
+      html += '</tbody></table>';
+    }
+    
+    if (c.probes) {
+      html += '<h4>' + t('🩺 探针') + '</h4><table class="data-table"><thead><tr><th>' + t('类型') + '</th><th>' + t('检查方式') + '</th><th>' + t('参数') + '</th></tr></thead><tbody>';
+      c.probes.forEach(p => {
+        html += '<tr><td>' + escapeHtml(p.type) + '</td><td class="field-path">' + escapeHtml(p.handler) + '</td><td>' + escapeHtml(p.timing || '-') + '</td></tr>';
+      });
+      html += '</tbody></table>';
+    }
+    html += '</div>';
+  });
+  return html;
+}
+
+// ========== 证书 ==========
+function renderCertificates(sources) {
+  let html = '';
+  sources.forEach(source => {
+    html += '<h4>🔑 ' + escapeHtml(source.key) + '</h4>';
+    if (source.error) {
+      html += '<div class="query-status error">' + escapeHtml(source.error) + '</div>';
+    }
+    if (source.chainIssue) {
+      html += '<div class="query-status error">⚠️ ' + escapeHtml(source.chainIssue) + '</div>';
+    }
+    source.certificates.forEach((cert, i) => {
+      const state = cert.expired ? 'expired' : (cert.expiringSoon ? 'expiring' : '');
+      let badge = t('%s 天后到期', cert.daysLeft);
+      if (cert.expired) badge = '<span class="lint-badge lint-error">' + t('已过期') + '</span>';
+      else if (cert.expiringSoon) badge = '<span class="lint-badge lint-warning">' + badge + '</span>';
+      const role = i === 0 && !cert.isCA ? t('叶子证书') : (cert.isCA ? 'CA' : t('证书'));
+      html += '<div class="cert-card ' + state + '">';
+      html += '<b>#' + (i + 1) + ' ' + role + '</b>' + (cert.selfSigned ? ' · ' + t('自签名') : '') + ' · ' + badge;
+      html += '<table class="data-table">';
+      html += '<tr><th>Subject</th><td class="field-path">' + escapeHtml(cert.subject) + '</td></tr>';
+      html += '<tr><th>' + t('签发者') + '</th><td class="field-path">' + escapeHtml(cert.issuer) + '</td></tr>';
+      if (cert.sans) {
+        html += '<tr><th>SAN</th><td>' + cert.sans.map(s => '<span class="label-chip static">' + escapeHtml(s) + '</span>').join('') + '</td></tr>';
+      }
+      html += '<tr><th>' + t('有效期') + '</th><td>' + escapeHtml(cert.notBefore) + ' ~ ' + escapeHtml(cert.notAfter) + '</td></tr>';
+      html += '<tr><th>' + t('序列号') + '</th><td class="field-path">' + escapeHtml(cert.serial) + '</td></tr>';
+      html += '<tr><th>SHA-256</th><td class="field-path">' + escapeHtml(cert.fingerprint) + '</td></tr>';
+      html += '</table></div>';
+    });
+  });
+  return html;
+}
+
+function closeModal() {
+  const modal = document.getElementById('resourceModal');
+  modal.style.display = 'none';
+  modal.classList.remove('fullscreen');
+  // 恢复背景滚动
+  document.body.classList.remove('modal-open');
+  // 重置全屏按钮
+  const fullscreenBtn = document.getElementById('fullscreenBtn');
+  fullscreenBtn.textContent = '🔍';
+  fullscreenBtn.title = t('放大到全屏 (F11)');
+}
+
+function toggleFullscreen() {
+  const modal = document.getElementById('resourceModal');
+  const fullscreenBtn = document.getElementById('fullscreenBtn');
+  
+  if (modal.classList.contains('fullscreen')) {
+    // 退出全屏
+    modal.classList.remove('fullscreen');
+    fullscreenBtn.textContent = '🔍';
+    fullscreenBtn.title = t('放大到全屏 (F11)');
+  } else {
+    // 进入全屏
+    modal.classList.add('fullscreen');
+    fullscreenBtn.textContent = '🔎';
+    fullscreenBtn.title = t('退出全屏 (F11)');
+  }
+}
+
+// 点击模态框外部关闭
+window.onclick = function(event) {
+  const modal = document.getElementById('resourceModal');
+  if (event.target === modal) {
+    closeModal();
+  }
+}
+
+// 键盘快捷键
+document.addEventListener('keydown', function(event) {
+  const modal = document.getElementById('resourceModal');
+  
+  if (event.key === 'Escape') {
+    closeModal();
+  } else if (event.key === 'F11' && modal.style.display === 'block') {
+    event.preventDefault();
+    toggleFullscreen();
+  }
+});
+
+// 阻止模态框内容滚动事件冒泡
+document.addEventListener('DOMContentLoaded', function() {
+  applyHash();
+
+  const modalContent = document.querySelector('.modal-content');
+  if (modalContent) {
+    modalContent.addEventListener('wheel', function(e) {
+      e.stopPropagation();
+    });
+    
+    modalContent.addEventListener('touchmove', function(e) {
+      e.stopPropagation();
+    });
+  }
+});
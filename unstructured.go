@@ -0,0 +1,112 @@
+package main
+
+import "fmt"
+
+// 按路径读取嵌套 map，任一层不存在或类型不符时返回 nil
+func nestedValue(obj map[string]interface{}, path ...string) interface{} {
+	var current interface{} = obj
+	for _, key := range path {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[key]
+	}
+	return current
+}
+
+func nestedMap(obj map[string]interface{}, path ...string) map[string]interface{} {
+	m, _ := nestedValue(obj, path...).(map[string]interface{})
+	return m
+}
+
+func nestedSlice(obj map[string]interface{}, path ...string) []interface{} {
+	s, _ := nestedValue(obj, path...).([]interface{})
+	return s
+}
+
+func nestedString(obj map[string]interface{}, path ...string) string {
+	return stringValue(nestedValue(obj, path...))
+}
+
+// 将 map[string]interface{} 转为字符串 map（标签、注解等）
+func stringMap(m map[string]interface{}) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		if v == nil {
+			out[k] = ""
+		} else {
+			out[k] = fmt.Sprintf("%v", v)
+		}
+	}
+	return out
+}
+
+// 资源标签
+func resourceLabels(info ResourceInfo) map[string]string {
+	return stringMap(nestedMap(info.Parsed, "metadata", "labels"))
+}
+
+// 资源注解
+func resourceAnnotations(info ResourceInfo) map[string]string {
+	return stringMap(nestedMap(info.Parsed, "metadata", "annotations"))
+}
+
+// 带有 Pod 模板的工作负载类型及其 PodSpec 所在路径
+var podSpecPaths = map[string][]string{
+	"Pod":         {"spec"},
+	"Deployment":  {"spec", "template", "spec"},
+	"StatefulSet": {"spec", "template", "spec"},
+	"DaemonSet":   {"spec", "template", "spec"},
+	"ReplicaSet":  {"spec", "template", "spec"},
+	"Job":         {"spec", "template", "spec"},
+	"CronJob":     {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// 工作负载的 PodSpec，非工作负载资源返回 nil
+func podSpec(info ResourceInfo) map[string]interface{} {
+	path, ok := podSpecPaths[info.Kind]
+	if !ok {
+		return nil
+	}
+	return nestedMap(info.Parsed, path...)
+}
+
+// PodSpec 中的一个容器
+type PodContainer struct {
+	Name string
+	Init bool
+	Spec map[string]interface{}
+}
+
+// 按 initContainers、containers 的顺序列出 PodSpec 中的容器
+func podContainers(spec map[string]interface{}) []PodContainer {
+	var containers []PodContainer
+	for _, field := range []string{"initContainers", "containers"} {
+		for _, item := range nestedSlice(spec, field) {
+			c, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			containers = append(containers, PodContainer{
+				Name: nestedString(c, "name"),
+				Init: field == "initContainers",
+				Spec: c,
+			})
+		}
+	}
+	return containers
+}
+
+// 工作负载 Pod 模板的 metadata（Pod 为其自身的 metadata）
+func podTemplateMetadata(info ResourceInfo) map[string]interface{} {
+	path, ok := podSpecPaths[info.Kind]
+	if !ok {
+		return nil
+	}
+	metadataPath := append(append([]string{}, path[:len(path)-1]...), "metadata")
+	return nestedMap(info.Parsed, metadataPath...)
+}